@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"servers/internal/database"
+	"servers/internal/views"
+
+	"github.com/google/uuid"
+)
+
+const (
+	viewFlushInterval    = 30 * time.Second
+	defaultAnalyticsDays = 30
+	maxAnalyticsDays     = 90
+)
+
+type ChirpAnalytics struct {
+	ChirpID uuid.UUID       `json:"chirp_id"`
+	Days    []DailyActivity `json:"days"`
+}
+
+// DailyActivity is what happened to a chirp on one UTC day. Days without
+// any views, likes or replies are left out.
+type DailyActivity struct {
+	Date    string `json:"date"`
+	Views   int64  `json:"views"`
+	Likes   int64  `json:"likes"`
+	Replies int64  `json:"replies"`
+}
+
+// recordViews counts an impression of each of the hydrated chirps towards
+// its author's analytics. Rechirps count towards the chirp they repost, and
+// authors are not counted looking at their own chirps.
+func (cfg *apiConfig) recordViews(viewerID uuid.UUID, chirps []Chirp) {
+	chirpIDs := make([]uuid.UUID, 0, len(chirps))
+	for _, chirp := range chirps {
+		if chirp.RechirpOf != nil {
+			if chirp.RechirpOf.Chirp == nil {
+				continue
+			}
+			chirp = *chirp.RechirpOf.Chirp
+		}
+		if chirp.UserID != viewerID {
+			chirpIDs = append(chirpIDs, chirp.ID)
+		}
+	}
+	cfg.viewCounter.Record(time.Now(), chirpIDs...)
+}
+
+// runViewFlusher periodically writes the views tallied in memory to the
+// database, so that recording them never slows down a read. It runs until
+// ctx is cancelled.
+func (cfg *apiConfig) runViewFlusher(ctx context.Context) {
+	ticker := time.NewTicker(viewFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		cfg.flushViews(ctx)
+	}
+}
+
+// flushViews adds the tallied views to the daily totals. If that fails they
+// are put back to be retried on the next flush.
+func (cfg *apiConfig) flushViews(ctx context.Context) {
+	counts := cfg.viewCounter.Flush()
+	if len(counts) == 0 {
+		return
+	}
+
+	params := database.AddChirpViewsParams{
+		ChirpIds: make([]uuid.UUID, len(counts)),
+		Days:     make([]time.Time, len(counts)),
+		Views:    make([]int64, len(counts)),
+	}
+	for i, count := range counts {
+		params.ChirpIds[i] = count.ChirpID
+		params.Days[i] = count.Day
+		params.Views[i] = count.Views
+	}
+	err := cfg.db.AddChirpViews(ctx, params)
+	if err != nil {
+		log.Printf("Error saving chirp views: %v", err)
+		cfg.viewCounter.Restore(counts)
+	}
+}
+
+// handlerAnalytics reports daily views, likes and replies for each of the
+// caller's chirps over the last days days (30 by default). It is a Chirpy
+// Red feature.
+func (cfg *apiConfig) handlerAnalytics(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	type analyticsResp struct {
+		Since  string           `json:"since"`
+		Chirps []ChirpAnalytics `json:"chirps"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	user, err := cfg.db.GetUser(r.Context(), userID)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	if !user.IsChirpyRed {
+		w.WriteHeader(403)
+		data, _ := json.Marshal(errorResp{Error: "Analytics are only available to Chirpy Red members"})
+		w.Write(data)
+		return
+	}
+
+	days := defaultAnalyticsDays
+	if daysFromQuery := r.URL.Query().Get("days"); len(daysFromQuery) > 0 {
+		days, err = strconv.Atoi(daysFromQuery)
+		if err != nil || days < 1 || days > maxAnalyticsDays {
+			w.WriteHeader(400)
+			data, _ := json.Marshal(errorResp{Error: fmt.Sprintf("days must be between 1 and %d", maxAnalyticsDays)})
+			w.Write(data)
+			return
+		}
+	}
+	since := views.Day(time.Now()).AddDate(0, 0, 1-days)
+
+	rows, err := cfg.db.GetChirpAnalytics(r.Context(), database.GetChirpAnalyticsParams{
+		UserID: userID,
+		Since:  since,
+	})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	// rows come ordered by chirp, then by day
+	chirps := []ChirpAnalytics{}
+	for _, row := range rows {
+		if len(chirps) == 0 || chirps[len(chirps)-1].ChirpID != row.ChirpID {
+			chirps = append(chirps, ChirpAnalytics{ChirpID: row.ChirpID})
+		}
+		last := &chirps[len(chirps)-1]
+		last.Days = append(last.Days, DailyActivity{
+			Date:    row.Day.Format(time.DateOnly),
+			Views:   row.Views,
+			Likes:   row.Likes,
+			Replies: row.Replies,
+		})
+	}
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(analyticsResp{
+		Since:  since.Format(time.DateOnly),
+		Chirps: chirps,
+	})
+	w.Write(data)
+}
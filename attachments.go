@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"servers/internal/database"
+	"servers/internal/media"
+	"servers/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxAttachmentsPerChirp = 4
+	// unattachedMediaMaxAge is how long an upload can wait for a chirp before
+	// the purger removes it.
+	unattachedMediaMaxAge = 24 * time.Hour
+)
+
+var (
+	errInvalidUpload = errors.New("invalid upload")
+	errMediaNotFound = errors.New("media not found or already attached")
+)
+
+type Attachment struct {
+	ID           uuid.UUID `json:"id"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	ContentType  string    `json:"content_type"`
+	Width        int32     `json:"width"`
+	Height       int32     `json:"height"`
+}
+
+func attachmentFromDB(attachment database.Attachment) Attachment {
+	return Attachment{
+		ID:           attachment.ID,
+		URL:          "/media/" + attachment.StorageKey,
+		ThumbnailURL: "/media/" + attachment.ThumbnailKey,
+		ContentType:  attachment.ContentType,
+		Width:        attachment.Width,
+		Height:       attachment.Height,
+	}
+}
+
+// storeUpload validates and stores one uploaded image and records it as an
+// attachment that is not part of any chirp yet. Errors wrapping
+// errInvalidUpload are caused by the upload itself.
+func (cfg *apiConfig) storeUpload(ctx context.Context, userID uuid.UUID, header *multipart.FileHeader) (database.Attachment, error) {
+	if header.Size > media.MaxBytes {
+		return database.Attachment{}, fmt.Errorf("%w: %v is larger than %d bytes", errInvalidUpload, header.Filename, media.MaxBytes)
+	}
+	file, err := header.Open()
+	if err != nil {
+		return database.Attachment{}, err
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, media.MaxBytes+1))
+	if err != nil {
+		return database.Attachment{}, err
+	}
+
+	processed, err := media.Process(data)
+	if err != nil {
+		return database.Attachment{}, fmt.Errorf("%w: %v", errInvalidUpload, err)
+	}
+
+	id := uuid.New()
+	storageKey := id.String() + processed.Ext
+	thumbnailKey := id.String() + "_thumb" + processed.ThumbnailExt
+	err = cfg.storage.Save(ctx, storageKey, processed.Data)
+	if err != nil {
+		return database.Attachment{}, err
+	}
+	err = cfg.storage.Save(ctx, thumbnailKey, processed.Thumbnail)
+	if err != nil {
+		cfg.deleteMedia(ctx, storageKey)
+		return database.Attachment{}, err
+	}
+
+	attachment, err := cfg.db.CreateAttachment(ctx, database.CreateAttachmentParams{
+		ID:           id,
+		UserID:       userID,
+		ContentType:  processed.ContentType,
+		SizeBytes:    int64(len(processed.Data)),
+		Width:        int32(processed.Width),
+		Height:       int32(processed.Height),
+		StorageKey:   storageKey,
+		ThumbnailKey: thumbnailKey,
+	})
+	if err != nil {
+		// without a row nothing would ever clean the files up
+		cfg.deleteMedia(ctx, storageKey, thumbnailKey)
+		return database.Attachment{}, err
+	}
+	return attachment, nil
+}
+
+// deleteMedia removes stored files, logging rather than returning failures
+// since there is nothing more the caller could do about them.
+func (cfg *apiConfig) deleteMedia(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		err := cfg.storage.Delete(ctx, key)
+		if err != nil {
+			log.Printf("Error deleting %v: %v", key, err)
+		}
+	}
+}
+
+// purgeUnattachedMedia removes uploads that were never attached to a chirp.
+// Media of chirps purged from the trash goes with them in purgeTrash.
+func (cfg *apiConfig) purgeUnattachedMedia(ctx context.Context) {
+	rows, err := cfg.db.DeleteUnattachedMedia(ctx, unattachedMediaMaxAge.Seconds())
+	if err != nil {
+		log.Printf("Error purging media: %v", err)
+		return
+	}
+	for _, row := range rows {
+		cfg.deleteMedia(ctx, row.StorageKey, row.ThumbnailKey)
+	}
+}
+
+func (cfg *apiConfig) handlerUploadMedia(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	r.Body = http.MaxBytesReader(w, r.Body, media.MaxBytes+(1<<20))
+	_, header, err := r.FormFile("file")
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "file is required"})
+		w.Write(data)
+		return
+	}
+
+	attachment, err := cfg.storeUpload(r.Context(), userID, header)
+	if errors.Is(err, errInvalidUpload) {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	w.WriteHeader(201)
+	data, _ := json.Marshal(attachmentFromDB(attachment))
+	w.Write(data)
+}
+
+// handlerServeMedia serves a stored file to viewers who can see what it is
+// attached to. Files anyone can see may be cached anywhere; the rest only by
+// the viewer's own client.
+func (cfg *apiConfig) handlerServeMedia(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	access, err := cfg.db.GetMediaAccess(r.Context(), database.GetMediaAccessParams{
+		ViewerID: cfg.viewer(r),
+		Key:      key,
+	})
+	if err != nil || !access.Visible {
+		w.WriteHeader(404)
+		w.Write([]byte("not found"))
+		return
+	}
+
+	file, err := cfg.storage.Open(r.Context(), key)
+	if errors.Is(err, storage.ErrNotFound) {
+		w.WriteHeader(404)
+		w.Write([]byte("not found"))
+		return
+	}
+	if err != nil {
+		// invalid keys end up here too, so don't reveal more than a 404
+		log.Printf("%v", err)
+		w.WriteHeader(404)
+		w.Write([]byte("not found"))
+		return
+	}
+	defer file.Close()
+
+	// keys are random and never reused, so their content never changes
+	w.Header().Set("Content-Type", mime.TypeByExtension(strings.ToLower(filepath.Ext(key))))
+	if access.Public {
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		w.Header().Set("Cache-Control", "private, no-cache")
+	}
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(200)
+	io.Copy(w, file)
+}
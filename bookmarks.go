@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"servers/internal/database"
+	"servers/internal/pagination"
+
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerBookmarkChirp(w http.ResponseWriter, r *http.Request) {
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+
+	chirp, err := cfg.db.GetChirp(r.Context(), database.GetChirpParams{ID: chirpID, ViewerID: userID})
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+	chirpID = originalID(chirp)
+
+	err = cfg.db.BookmarkChirp(r.Context(), database.BookmarkChirpParams{UserID: userID, ChirpID: chirpID})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerUnbookmarkChirp(w http.ResponseWriter, r *http.Request) {
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+	// undoing through a rechirp applies to the original, as bookmarking does
+	chirp, err := cfg.db.GetChirp(r.Context(), database.GetChirpParams{ID: chirpID, ViewerID: userID})
+	if err == nil {
+		chirpID = originalID(chirp)
+	}
+
+	err = cfg.db.UnbookmarkChirp(r.Context(), database.UnbookmarkChirpParams{UserID: userID, ChirpID: chirpID})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+// handlerBookmarks lists the caller's bookmarks, ordered by when they were
+// bookmarked rather than by when the chirps were posted.
+func (cfg *apiConfig) handlerBookmarks(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	type bookmarkedChirp struct {
+		Chirp
+		BookmarkedAt time.Time `json:"bookmarked_at"`
+	}
+
+	type bookmarksResp struct {
+		Chirps     []bookmarkedChirp `json:"chirps"`
+		NextCursor string            `json:"next_cursor,omitempty"`
+		PrevCursor string            `json:"prev_cursor,omitempty"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	page, err := parsePageRequest(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+
+	cursorCreatedAt, cursorID := page.cursorArgs()
+	var rows []database.GetBookmarksAscendingRow
+	if page.ascending() {
+		rows, err = cfg.db.GetBookmarksAscending(r.Context(), database.GetBookmarksAscendingParams{
+			UserID:          userID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        page.rowLimit(),
+		})
+	} else {
+		var descRows []database.GetBookmarksDescendingRow
+		descRows, err = cfg.db.GetBookmarksDescending(r.Context(), database.GetBookmarksDescendingParams{
+			UserID:          userID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        page.rowLimit(),
+		})
+		for _, row := range descRows {
+			rows = append(rows, database.GetBookmarksAscendingRow(row))
+		}
+	}
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	rows, nextCursor, prevCursor := paginate(rows, page, func(row database.GetBookmarksAscendingRow) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: row.BookmarkedAt, ID: row.ID}
+	})
+
+	chirps := make([]database.Chirp, len(rows))
+	for i, row := range rows {
+		chirps[i] = database.Chirp{
+			ID:        row.ID,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+			Body:      row.Body,
+			UserID:    row.UserID,
+			ParentID:  row.ParentID,
+			RechirpOf: row.RechirpOf,
+			QuoteOf:   row.QuoteOf,
+
+			ContentWarning: row.ContentWarning,
+			Sensitive:      row.Sensitive,
+			Visibility:     row.Visibility,
+		}
+	}
+	apiChirps, err := cfg.hydrateChirps(r.Context(), userID, chirps)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	cfg.recordViews(userID, apiChirps)
+
+	bookmarked := make([]bookmarkedChirp, len(rows))
+	for i, row := range rows {
+		bookmarked[i] = bookmarkedChirp{
+			Chirp:        apiChirps[i],
+			BookmarkedAt: row.BookmarkedAt,
+		}
+	}
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(bookmarksResp{
+		Chirps:     bookmarked,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	})
+	w.Write(data)
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"servers/internal/database"
+
+	"github.com/google/uuid"
+)
+
+type ChirpRevision struct {
+	ID         uuid.UUID `json:"id"`
+	ChirpID    uuid.UUID `json:"chirp_id"`
+	Body       string    `json:"body"`
+	CreatedAt  time.Time `json:"created_at"`
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+// editWindowFor returns how long after posting the user can still edit a
+// chirp. Zero means there is no limit.
+func (cfg *apiConfig) editWindowFor(user database.User) time.Duration {
+	if cfg.editWindow == 0 {
+		return 0
+	}
+	if user.IsChirpyRed {
+		return cfg.redEditWindow
+	}
+	return cfg.editWindow
+}
+
+func (cfg *apiConfig) handlerEditChirp(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Body string `json:"body"`
+	}
+
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+	chirp, err := cfg.db.GetChirpIncludingScheduled(r.Context(), chirpID)
+	if err != nil || (chirp.PublishAt.Valid && chirp.UserID != userID) {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+
+	// check if the user is the owner of the token
+	if chirp.UserID != userID {
+		w.WriteHeader(403)
+		w.Write([]byte("Unauthorized"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if chirp.RechirpOf.Valid {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Rechirps cannot be edited"})
+		w.Write(data)
+		return
+	}
+
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Invalid params"})
+		w.Write(data)
+		return
+	}
+
+	chirpFilter, err := cfg.chirpFilter(r.Context())
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	body, flagged, err := validateChirpBody(chirpFilter, params.Body)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+
+	user, err := cfg.db.GetUser(r.Context(), userID)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	chirp, err = cfg.editChirp(r.Context(), chirpID, body, flagged, cfg.editWindowFor(user))
+	if errors.Is(err, sql.ErrNoRows) {
+		w.WriteHeader(403)
+		data, _ := json.Marshal(errorResp{Error: "Edit window has passed"})
+		w.Write(data)
+		return
+	}
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	chirpsToReturn, err := cfg.hydrateChirps(r.Context(), userID, []database.Chirp{chirp})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(chirpsToReturn[0])
+	w.Write(data)
+}
+
+// handlerChirpRevisions lists the previous bodies of a chirp, oldest first.
+func (cfg *apiConfig) handlerChirpRevisions(w http.ResponseWriter, r *http.Request) {
+	chirpID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+	_, err = cfg.db.GetChirp(r.Context(), database.GetChirpParams{ID: chirpID, ViewerID: cfg.viewer(r)})
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+
+	revisions, err := cfg.db.GetChirpRevisions(r.Context(), chirpID)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	revisionsToReturn := make([]ChirpRevision, len(revisions))
+	for i, revision := range revisions {
+		revisionsToReturn[i] = ChirpRevision{
+			ID:         revision.ID,
+			ChirpID:    revision.ChirpID,
+			Body:       revision.Body,
+			CreatedAt:  revision.CreatedAt,
+			ReplacedAt: revision.ReplacedAt,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	data, _ := json.Marshal(revisionsToReturn)
+	w.Write(data)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"servers/internal/database"
+)
+
+func TestEditRemovingMentionRevokesAccess(t *testing.T) {
+	cfg := newTestConfig(t)
+	ctx := context.Background()
+	author := createTestUser(t, cfg, "author")
+	friend := createTestUser(t, cfg, "friend")
+
+	chirp, err := cfg.createChirp(ctx, database.CreateChirpParams{
+		Body:       "hi @friend",
+		UserID:     author.ID,
+		Visibility: visibilityMentioned,
+	}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = cfg.db.GetChirp(ctx, database.GetChirpParams{ID: chirp.ID, ViewerID: friend.ID})
+	if err != nil {
+		t.Fatalf("mentioned user cannot see the chirp: %v", err)
+	}
+
+	_, err = cfg.editChirp(ctx, chirp.ID, "hi everyone", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = cfg.db.GetChirp(ctx, database.GetChirpParams{ID: chirp.ID, ViewerID: friend.ID})
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("user edited out of the chirp can still see it, err = %v", err)
+	}
+	var notifications int
+	err = cfg.dbConn.QueryRow("SELECT COUNT(*) FROM notifications WHERE user_id = $1", friend.ID).Scan(&notifications)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notifications != 0 {
+		t.Errorf("user edited out of the chirp still has %d notifications", notifications)
+	}
+}
+
+func TestEditKeepingMentionDoesNotNotifyAgain(t *testing.T) {
+	cfg := newTestConfig(t)
+	ctx := context.Background()
+	author := createTestUser(t, cfg, "author")
+	friend := createTestUser(t, cfg, "friend")
+
+	chirp, err := cfg.createChirp(ctx, database.CreateChirpParams{
+		Body:       "hi @friend",
+		UserID:     author.ID,
+		Visibility: visibilityMentioned,
+	}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = cfg.editChirp(ctx, chirp.ID, "hello @friend", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = cfg.db.GetChirp(ctx, database.GetChirpParams{ID: chirp.ID, ViewerID: friend.ID})
+	if err != nil {
+		t.Errorf("mentioned user cannot see the edited chirp: %v", err)
+	}
+	var notifications int
+	err = cfg.dbConn.QueryRow("SELECT COUNT(*) FROM notifications WHERE user_id = $1", friend.ID).Scan(&notifications)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notifications != 1 {
+		t.Errorf("got %d notifications, want 1", notifications)
+	}
+}
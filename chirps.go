@@ -0,0 +1,725 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"time"
+
+	"servers/internal/database"
+	"servers/internal/media"
+	"servers/internal/pagination"
+	"servers/internal/parse"
+
+	"github.com/google/uuid"
+)
+
+// createChirpRequest is the body of POST /api/chirps. Clients either send it
+// as JSON, referencing earlier POST /api/media uploads in media_ids, or as a
+// multipart form with the same fields and the images attached as "media".
+type createChirpRequest struct {
+	Body      string       `json:"body"`
+	InReplyTo *uuid.UUID   `json:"in_reply_to"`
+	MediaIDs  []uuid.UUID  `json:"media_ids"`
+	PublishAt *time.Time   `json:"publish_at"`
+	QuoteOf   *uuid.UUID   `json:"quote_of"`
+	Poll      *pollRequest `json:"poll"`
+
+	ContentWarning *string `json:"content_warning"`
+	Sensitive      bool    `json:"sensitive"`
+	Visibility     string  `json:"visibility"`
+
+	uploads []*multipart.FileHeader
+}
+
+func decodeCreateChirpRequest(w http.ResponseWriter, r *http.Request) (createChirpRequest, error) {
+	params := createChirpRequest{}
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if contentType != "multipart/form-data" {
+		r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+		decoder := json.NewDecoder(r.Body)
+		err := decoder.Decode(&params)
+		return params, err
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxAttachmentsPerChirp*media.MaxBytes+(1<<20))
+	err := r.ParseMultipartForm(media.MaxBytes)
+	if err != nil {
+		return params, err
+	}
+	params.Body = r.FormValue("body")
+	if inReplyTo := r.FormValue("in_reply_to"); len(inReplyTo) > 0 {
+		parentID, err := uuid.Parse(inReplyTo)
+		if err != nil {
+			return params, err
+		}
+		params.InReplyTo = &parentID
+	}
+	if contentWarning := r.FormValue("content_warning"); len(contentWarning) > 0 {
+		params.ContentWarning = &contentWarning
+	}
+	params.Sensitive = r.FormValue("sensitive") == "true"
+	params.Visibility = r.FormValue("visibility")
+	if quoteOf := r.FormValue("quote_of"); len(quoteOf) > 0 {
+		quotedID, err := uuid.Parse(quoteOf)
+		if err != nil {
+			return params, err
+		}
+		params.QuoteOf = &quotedID
+	}
+	if pollOptions := r.MultipartForm.Value["poll_options"]; len(pollOptions) > 0 {
+		duration, err := strconv.ParseInt(r.FormValue("poll_duration_seconds"), 10, 64)
+		if err != nil {
+			return params, err
+		}
+		params.Poll = &pollRequest{Options: pollOptions, DurationSeconds: duration}
+	}
+	if publishAt := r.FormValue("publish_at"); len(publishAt) > 0 {
+		t, err := time.Parse(time.RFC3339, publishAt)
+		if err != nil {
+			return params, err
+		}
+		params.PublishAt = &t
+	}
+	for _, value := range r.MultipartForm.Value["media_ids"] {
+		mediaID, err := uuid.Parse(value)
+		if err != nil {
+			return params, err
+		}
+		params.MediaIDs = append(params.MediaIDs, mediaID)
+	}
+	params.uploads = r.MultipartForm.File["media"]
+	return params, nil
+}
+
+func chirpFromDB(chirp database.Chirp) Chirp {
+	apiChirp := Chirp{
+		ID:        chirp.ID,
+		CreatedAt: chirp.CreatedAt,
+		UpdatedAt: chirp.UpdatedAt,
+		Body:      chirp.Body,
+		UserID:    chirp.UserID,
+		Sensitive: chirp.Sensitive,
+
+		Visibility:  chirp.Visibility,
+		Attachments: []Attachment{},
+	}
+	if chirp.ContentWarning.Valid {
+		contentWarning := chirp.ContentWarning.String
+		apiChirp.ContentWarning = &contentWarning
+	}
+	if chirp.ParentID.Valid {
+		parentID := chirp.ParentID.UUID
+		apiChirp.ParentID = &parentID
+	}
+	if chirp.PublishAt.Valid {
+		publishAt := chirp.PublishAt.Time
+		apiChirp.PublishAt = &publishAt
+	}
+	return apiChirp
+}
+
+// createChirp inserts a chirp together with the hashtags and mentions parsed
+// from its body, and notifies mentioned users, in a single transaction. For a
+// scheduled chirp the hashtags and mentions are left to publishDueChirps.
+// flagged are the filtered words found by validateChirpBody, if any,
+// mediaIDs are uploads of the author's to attach in the given order and poll
+// is an optional poll to attach.
+func (cfg *apiConfig) createChirp(ctx context.Context, params database.CreateChirpParams, flagged []string, mediaIDs []uuid.UUID, poll *newPoll) (database.Chirp, error) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	chirp, err := insertChirp(ctx, qtx, params, flagged, mediaIDs, poll)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	return chirp, tx.Commit()
+}
+
+// insertChirp does the work of createChirp inside the caller's transaction.
+func insertChirp(ctx context.Context, qtx *database.Queries, params database.CreateChirpParams, flagged []string, mediaIDs []uuid.UUID, poll *newPoll) (database.Chirp, error) {
+	chirp, err := qtx.CreateChirp(ctx, params)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	err = flagChirp(ctx, qtx, chirp, flagged)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	if len(mediaIDs) > 0 {
+		attached, err := qtx.AttachMedia(ctx, database.AttachMediaParams{
+			ChirpID: uuid.NullUUID{UUID: chirp.ID, Valid: true},
+			Ids:     mediaIDs,
+			UserID:  chirp.UserID,
+		})
+		if err != nil {
+			return database.Chirp{}, err
+		}
+		if int(attached) != len(mediaIDs) {
+			return database.Chirp{}, errMediaNotFound
+		}
+	}
+
+	if poll != nil {
+		err = addPoll(ctx, qtx, chirp, poll)
+		if err != nil {
+			return database.Chirp{}, err
+		}
+	}
+
+	if !chirp.PublishAt.Valid {
+		err = addTags(ctx, qtx, chirp)
+		if err != nil {
+			return database.Chirp{}, err
+		}
+
+		err = addMentions(ctx, qtx, chirp)
+		if err != nil {
+			return database.Chirp{}, err
+		}
+	}
+
+	return chirp, nil
+}
+
+// editChirp replaces the body of a chirp, keeping the old body as a revision
+// and re-syncing tags and mentions. editWindow limits how long after creation
+// the chirp can still be edited; zero means no limit. It returns
+// sql.ErrNoRows when the window has passed. Scheduled chirps can be edited
+// until they are published.
+func (cfg *apiConfig) editChirp(ctx context.Context, chirpID uuid.UUID, body string, flagged []string, editWindow time.Duration) (database.Chirp, error) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	// lock the row so concurrent edits each record the body they replaced
+	previous, err := qtx.GetChirpForUpdate(ctx, chirpID)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	editWindowSeconds := sql.NullFloat64{}
+	if editWindow > 0 {
+		editWindowSeconds = sql.NullFloat64{Float64: editWindow.Seconds(), Valid: true}
+	}
+	chirp, err := qtx.UpdateChirpBody(ctx, database.UpdateChirpBodyParams{
+		Body:              body,
+		ID:                chirpID,
+		EditWindowSeconds: editWindowSeconds,
+	})
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	_, err = qtx.CreateChirpRevision(ctx, database.CreateChirpRevisionParams{
+		ChirpID:   previous.ID,
+		Body:      previous.Body,
+		CreatedAt: previous.UpdatedAt,
+	})
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	err = flagChirp(ctx, qtx, chirp, flagged)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	if !chirp.PublishAt.Valid {
+		err = qtx.DeleteChirpTags(ctx, chirp.ID)
+		if err != nil {
+			return database.Chirp{}, err
+		}
+		err = addTags(ctx, qtx, chirp)
+		if err != nil {
+			return database.Chirp{}, err
+		}
+
+		err = removeMentions(ctx, qtx, chirp)
+		if err != nil {
+			return database.Chirp{}, err
+		}
+		err = addMentions(ctx, qtx, chirp)
+		if err != nil {
+			return database.Chirp{}, err
+		}
+	}
+
+	return chirp, tx.Commit()
+}
+
+func addTags(ctx context.Context, qtx *database.Queries, chirp database.Chirp) error {
+	for _, name := range parse.Hashtags(chirp.Body) {
+		tag, err := qtx.UpsertTag(ctx, name)
+		if err != nil {
+			return err
+		}
+		err = qtx.AddChirpTag(ctx, database.AddChirpTagParams{ChirpID: chirp.ID, TagID: tag.ID})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addMentions stores a mention for every user whose handle is mentioned in
+// the chirp's body and notifies the ones that were not mentioned in it
+// before.
+func addMentions(ctx context.Context, qtx *database.Queries, chirp database.Chirp) error {
+	names := parse.Mentions(chirp.Body)
+	if len(names) == 0 {
+		return nil
+	}
+
+	userIDs, err := qtx.ResolveMentions(ctx, names)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		added, err := qtx.AddMention(ctx, database.AddMentionParams{ChirpID: chirp.ID, UserID: userID})
+		if err != nil {
+			return err
+		}
+		if added == 0 || userID == chirp.UserID {
+			continue
+		}
+		err = qtx.CreateNotification(ctx, database.CreateNotificationParams{
+			UserID:  userID,
+			ActorID: chirp.UserID,
+			Kind:    notificationKindMention,
+			ChirpID: uuid.NullUUID{UUID: chirp.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeMentions drops the mentions of users who are no longer mentioned in
+// the chirp's body, which also takes away their access to a chirp only
+// visible to mentioned users, and the notifications they were sent for them.
+func removeMentions(ctx context.Context, qtx *database.Queries, chirp database.Chirp) error {
+	keep := []uuid.UUID{}
+	names := parse.Mentions(chirp.Body)
+	if len(names) > 0 {
+		userIDs, err := qtx.ResolveMentions(ctx, names)
+		if err != nil {
+			return err
+		}
+		keep = append(keep, userIDs...)
+	}
+
+	return qtx.DeleteChirpMentions(ctx, database.DeleteChirpMentionsParams{
+		ChirpID: chirp.ID,
+		Keep:    keep,
+		Kind:    notificationKindMention,
+	})
+}
+
+// hydrateChirps converts database chirps into their API form and fills in the
+// aggregate fields that are stored outside of the chirps row, embedding the
+// chirps that rechirps and quotes refer to. viewerID is the caller, or
+// uuid.Nil for anonymous requests.
+func (cfg *apiConfig) hydrateChirps(ctx context.Context, viewerID uuid.UUID, chirps []database.Chirp) ([]Chirp, error) {
+	apiChirps, err := cfg.fillChirps(ctx, viewerID, chirps)
+	if err != nil {
+		return nil, err
+	}
+	err = cfg.embedReferencedChirps(ctx, viewerID, chirps, apiChirps)
+	if err != nil {
+		return nil, err
+	}
+	return apiChirps, nil
+}
+
+// fillChirps does the work of hydrateChirps except for embedding the chirps
+// that rechirps and quotes refer to.
+func (cfg *apiConfig) fillChirps(ctx context.Context, viewerID uuid.UUID, chirps []database.Chirp) ([]Chirp, error) {
+	apiChirps := make([]Chirp, len(chirps))
+	ids := make([]uuid.UUID, len(chirps))
+	positions := make(map[uuid.UUID]int, len(chirps))
+	for i, chirp := range chirps {
+		apiChirps[i] = chirpFromDB(chirp)
+		ids[i] = chirp.ID
+		positions[chirp.ID] = i
+	}
+	if len(chirps) == 0 {
+		return apiChirps, nil
+	}
+
+	replyCounts, err := cfg.db.GetReplyCounts(ctx, database.GetReplyCountsParams{Ids: ids, ViewerID: viewerID})
+	if err != nil {
+		return nil, err
+	}
+	for _, replyCount := range replyCounts {
+		apiChirps[positions[replyCount.ParentID.UUID]].ReplyCount = replyCount.ReplyCount
+	}
+
+	rechirpCounts, err := cfg.db.GetRechirpCounts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, rechirpCount := range rechirpCounts {
+		apiChirps[positions[rechirpCount.RechirpOf.UUID]].RechirpCount = rechirpCount.RechirpCount
+	}
+
+	likeCounts, err := cfg.db.GetLikeCounts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, likeCount := range likeCounts {
+		apiChirps[positions[likeCount.ChirpID]].LikeCount = likeCount.LikeCount
+	}
+
+	err = cfg.collapseChirps(ctx, viewerID, apiChirps)
+	if err != nil {
+		return nil, err
+	}
+
+	pinnedIDs, err := cfg.db.GetPinnedChirpIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, pinnedID := range pinnedIDs {
+		apiChirps[positions[pinnedID]].Pinned = true
+	}
+
+	polls, err := cfg.pollsForChirps(ctx, viewerID, ids)
+	if err != nil {
+		return nil, err
+	}
+	for chirpID, poll := range polls {
+		apiChirps[positions[chirpID]].Poll = poll
+	}
+
+	attachments, err := cfg.db.GetAttachmentsForChirps(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, attachment := range attachments {
+		apiChirp := &apiChirps[positions[attachment.ChirpID.UUID]]
+		apiChirp.Attachments = append(apiChirp.Attachments, attachmentFromDB(attachment))
+	}
+
+	if viewerID != uuid.Nil {
+		likedIDs, err := cfg.db.GetLikedChirpIDs(ctx, database.GetLikedChirpIDsParams{UserID: viewerID, Ids: ids})
+		if err != nil {
+			return nil, err
+		}
+		for _, likedID := range likedIDs {
+			apiChirps[positions[likedID]].LikedByMe = true
+		}
+
+		bookmarkedIDs, err := cfg.db.GetBookmarkedChirpIDs(ctx, database.GetBookmarkedChirpIDsParams{UserID: viewerID, Ids: ids})
+		if err != nil {
+			return nil, err
+		}
+		for _, bookmarkedID := range bookmarkedIDs {
+			apiChirps[positions[bookmarkedID]].BookmarkedByMe = true
+		}
+	}
+
+	return apiChirps, nil
+}
+
+type chirpPageResp struct {
+	Chirps     []Chirp `json:"chirps"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+	PrevCursor string  `json:"prev_cursor,omitempty"`
+}
+
+// writeChirpPage trims rows fetched for page, hydrates them and writes them
+// out in the envelope shared by every chirp listing. pinned chirps, if any,
+// are put ahead of the page.
+func (cfg *apiConfig) writeChirpPage(w http.ResponseWriter, r *http.Request, pinned, chirps []database.Chirp, page pageRequest) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	chirps, nextCursor, prevCursor := paginate(chirps, page, func(chirp database.Chirp) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: chirp.CreatedAt, ID: chirp.ID}
+	})
+
+	viewerID := cfg.viewer(r)
+	pageChirps := append(pinned, chirps...)
+	apiChirps, err := cfg.hydrateChirps(r.Context(), viewerID, pageChirps)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	cfg.recordViews(viewerID, apiChirps)
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(chirpPageResp{
+		Chirps:     apiChirps,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	})
+	w.Write(data)
+}
+
+func (cfg *apiConfig) handlerTimeline(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	page, err := parsePageRequest(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+
+	cursorCreatedAt, cursorID := page.cursorArgs()
+	var chirps []database.Chirp
+	if page.ascending() {
+		chirps, err = cfg.db.GetTimelineAscending(r.Context(), database.GetTimelineAscendingParams{
+			UserID:          userID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        page.rowLimit(),
+		})
+	} else {
+		chirps, err = cfg.db.GetTimelineDescending(r.Context(), database.GetTimelineDescendingParams{
+			UserID:          userID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        page.rowLimit(),
+		})
+	}
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	cfg.writeChirpPage(w, r, nil, chirps, page)
+}
+
+func (cfg *apiConfig) handlerChirpThread(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	type threadReply struct {
+		Chirp
+		Depth int32 `json:"depth"`
+	}
+
+	type threadResp struct {
+		Ancestors  []Chirp       `json:"ancestors"`
+		Chirp      Chirp         `json:"chirp"`
+		Replies    []threadReply `json:"replies"`
+		NextCursor string        `json:"next_cursor,omitempty"`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	chirpID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		data, _ := json.Marshal(errorResp{Error: "Chirp not found"})
+		w.Write(data)
+		return
+	}
+
+	page, err := parsePageRequest(r.URL.Query())
+	if err == nil && (page.before || page.descending) {
+		err = fmt.Errorf("replies can only be paged forward")
+	}
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+
+	viewerID := cfg.viewer(r)
+	chirp, err := cfg.db.GetChirp(r.Context(), database.GetChirpParams{ID: chirpID, ViewerID: viewerID})
+	if err != nil {
+		w.WriteHeader(404)
+		data, _ := json.Marshal(errorResp{Error: "Chirp not found"})
+		w.Write(data)
+		return
+	}
+
+	ancestors, err := cfg.db.GetChirpAncestors(r.Context(), database.GetChirpAncestorsParams{ID: chirpID, ViewerID: viewerID})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	cursorCreatedAt, cursorID := page.cursorArgs()
+	descendants, err := cfg.db.GetChirpDescendants(r.Context(), database.GetChirpDescendantsParams{
+		RootID:          chirpID,
+		ViewerID:        viewerID,
+		CursorCreatedAt: cursorCreatedAt,
+		CursorID:        cursorID,
+		RowLimit:        page.rowLimit(),
+	})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	descendants, nextCursor, _ := paginate(descendants, page, func(row database.GetChirpDescendantsRow) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: row.CreatedAt, ID: row.ID}
+	})
+
+	// hydrate the whole thread in one go so reply counts take a single query
+	chirps := make([]database.Chirp, 0, len(ancestors)+1+len(descendants))
+	chirps = append(chirps, ancestors...)
+	chirps = append(chirps, chirp)
+	for _, row := range descendants {
+		chirps = append(chirps, database.Chirp{
+			ID:        row.ID,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+			Body:      row.Body,
+			UserID:    row.UserID,
+			ParentID:  row.ParentID,
+			QuoteOf:   row.QuoteOf,
+
+			ContentWarning: row.ContentWarning,
+			Sensitive:      row.Sensitive,
+			Visibility:     row.Visibility,
+		})
+	}
+	apiChirps, err := cfg.hydrateChirps(r.Context(), viewerID, chirps)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	cfg.recordViews(viewerID, apiChirps)
+
+	replies := make([]threadReply, len(descendants))
+	for i, row := range descendants {
+		replies[i] = threadReply{
+			Chirp: apiChirps[len(ancestors)+1+i],
+			Depth: row.Depth,
+		}
+	}
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(threadResp{
+		Ancestors:  apiChirps[:len(ancestors)],
+		Chirp:      apiChirps[len(ancestors)],
+		Replies:    replies,
+		NextCursor: nextCursor,
+	})
+	w.Write(data)
+}
+
+func (cfg *apiConfig) handlerLikeChirp(w http.ResponseWriter, r *http.Request) {
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+
+	chirp, err := cfg.db.GetChirp(r.Context(), database.GetChirpParams{ID: chirpID, ViewerID: userID})
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+	chirpID = originalID(chirp)
+
+	// the primary key on likes makes repeated or concurrent likes a no-op
+	err = cfg.db.LikeChirp(r.Context(), database.LikeChirpParams{UserID: userID, ChirpID: chirpID})
+	// the chirp was purged after it was looked up
+	if isForeignKeyViolation(err) {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerUnlikeChirp(w http.ResponseWriter, r *http.Request) {
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+	// undoing through a rechirp applies to the original, as liking does
+	chirp, err := cfg.db.GetChirp(r.Context(), database.GetChirpParams{ID: chirpID, ViewerID: userID})
+	if err == nil {
+		chirpID = originalID(chirp)
+	}
+
+	err = cfg.db.UnlikeChirp(r.Context(), database.UnlikeChirpParams{UserID: userID, ChirpID: chirpID})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	w.WriteHeader(204)
+}
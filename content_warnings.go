@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"servers/internal/database"
+
+	"github.com/google/uuid"
+)
+
+const maxContentWarningLength = 100
+
+// Values of a user's sensitive_content preference: whether chirps behind a
+// content warning or marked sensitive are collapsed or shown expanded.
+const (
+	sensitiveContentHide   = "hide"
+	sensitiveContentExpand = "expand"
+)
+
+type Preferences struct {
+	SensitiveContent string `json:"sensitive_content"`
+}
+
+// validateContentWarning trims a content warning and checks its length. An
+// empty warning is the same as none.
+func validateContentWarning(warning *string) (sql.NullString, error) {
+	if warning == nil {
+		return sql.NullString{}, nil
+	}
+	trimmed := strings.TrimSpace(*warning)
+	if len(trimmed) == 0 {
+		return sql.NullString{}, nil
+	}
+	if utf8.RuneCountInString(trimmed) > maxContentWarningLength {
+		return sql.NullString{}, fmt.Errorf("Content warning can be at most %d characters", maxContentWarningLength)
+	}
+	return sql.NullString{String: trimmed, Valid: true}, nil
+}
+
+func (cfg *apiConfig) handlerPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	user, err := cfg.db.GetUser(r.Context(), userID)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	data, _ := json.Marshal(Preferences{SensitiveContent: user.SensitiveContent})
+	w.Write(data)
+}
+
+func (cfg *apiConfig) handlerUpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	params := Preferences{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Invalid params"})
+		w.Write(data)
+		return
+	}
+	if params.SensitiveContent != sensitiveContentHide && params.SensitiveContent != sensitiveContentExpand {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{
+			Error: fmt.Sprintf("sensitive_content must be %q or %q", sensitiveContentHide, sensitiveContentExpand),
+		})
+		w.Write(data)
+		return
+	}
+
+	user, err := cfg.db.UpdateSensitiveContent(r.Context(), database.UpdateSensitiveContentParams{
+		SensitiveContent: params.SensitiveContent,
+		ID:               userID,
+	})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(Preferences{SensitiveContent: user.SensitiveContent})
+	w.Write(data)
+}
+
+// handlerSetContentWarning lets moderators put a content warning on, or take
+// it off, any chirp.
+func (cfg *apiConfig) handlerSetContentWarning(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		ContentWarning *string `json:"content_warning"`
+		Sensitive      bool    `json:"sensitive"`
+	}
+
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	if !cfg.authenticateAdmin(r) {
+		w.WriteHeader(401)
+		w.Write([]byte("API key incorrect"))
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Invalid params"})
+		w.Write(data)
+		return
+	}
+	contentWarning, err := validateContentWarning(params.ContentWarning)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+
+	chirp, err := cfg.db.SetChirpContentWarning(r.Context(), database.SetChirpContentWarningParams{
+		ContentWarning: contentWarning,
+		Sensitive:      params.Sensitive,
+		ID:             chirpID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		w.WriteHeader(404)
+		data, _ := json.Marshal(errorResp{Error: "Chirp not found"})
+		w.Write(data)
+		return
+	}
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(chirpFromDB(chirp))
+	w.Write(data)
+}
+
+// collapseChirps marks chirps with a content warning or sensitive media as
+// collapsed unless the viewer prefers to have them expanded.
+func (cfg *apiConfig) collapseChirps(ctx context.Context, viewerID uuid.UUID, apiChirps []Chirp) error {
+	hasSensitive := false
+	for _, apiChirp := range apiChirps {
+		if apiChirp.ContentWarning != nil || apiChirp.Sensitive {
+			hasSensitive = true
+			break
+		}
+	}
+	if !hasSensitive {
+		return nil
+	}
+
+	expand := false
+	if viewerID != uuid.Nil {
+		viewer, err := cfg.db.GetUser(ctx, viewerID)
+		if err != nil {
+			return err
+		}
+		expand = viewer.SensitiveContent == sensitiveContentExpand
+	}
+	for i := range apiChirps {
+		apiChirps[i].Collapsed = !expand && (apiChirps[i].ContentWarning != nil || apiChirps[i].Sensitive)
+	}
+	return nil
+}
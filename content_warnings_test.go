@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"servers/internal/database"
+
+	"github.com/google/uuid"
+)
+
+func TestHideSensitiveHidesRechirps(t *testing.T) {
+	cfg := newTestConfig(t)
+	ctx := context.Background()
+	author := createTestUser(t, cfg, "author")
+	rechirper := createTestUser(t, cfg, "rechirper")
+	viewer := createTestUser(t, cfg, "viewer")
+
+	sensitive, err := cfg.createChirp(ctx, database.CreateChirpParams{
+		Body:       "sensitive",
+		UserID:     author.ID,
+		Sensitive:  true,
+		Visibility: visibilityPublic,
+	}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	warned, err := cfg.createChirp(ctx, database.CreateChirpParams{
+		Body:           "warned",
+		UserID:         author.ID,
+		ContentWarning: sql.NullString{String: "spoilers", Valid: true},
+		Visibility:     visibilityPublic,
+	}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := cfg.createChirp(ctx, database.CreateChirpParams{
+		Body:       "plain",
+		UserID:     author.ID,
+		Visibility: visibilityPublic,
+	}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rechirps := map[uuid.UUID]bool{}
+	for _, original := range []database.Chirp{sensitive, warned, plain} {
+		rechirp, err := cfg.db.CreateRechirp(ctx, database.CreateRechirpParams{
+			UserID:    rechirper.ID,
+			RechirpOf: uuid.NullUUID{UUID: original.ID, Valid: true},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rechirps[rechirp.ID] = original.ID == plain.ID
+	}
+
+	for _, hideSensitive := range []bool{false, true} {
+		chirps, err := cfg.db.GetChirpsDescending(ctx, database.GetChirpsDescendingParams{
+			ViewerID:      viewer.ID,
+			HideSensitive: hideSensitive,
+			AuthorID:      rechirper.ID,
+			RowLimit:      10,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := 0
+		for _, shownWhenHiding := range rechirps {
+			if !hideSensitive || shownWhenHiding {
+				want++
+			}
+		}
+		if len(chirps) != want {
+			t.Fatalf("hide_sensitive=%v: got %d rechirps, want %d", hideSensitive, len(chirps), want)
+		}
+		for _, chirp := range chirps {
+			if hideSensitive && !rechirps[chirp.ID] {
+				t.Errorf("hide_sensitive=true: rechirp %v of a sensitive chirp was returned", chirp.ID)
+			}
+		}
+	}
+}
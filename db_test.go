@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"servers/internal/database"
+	"servers/internal/filter"
+	"servers/internal/views"
+)
+
+// newTestConfig returns an apiConfig backed by a schema of its own in the
+// database at TEST_DB_URL, with every migration applied. The schema is
+// dropped when the test ends. Tests that need a database are skipped when
+// TEST_DB_URL is not set.
+func newTestConfig(t *testing.T) *apiConfig {
+	t.Helper()
+	dbURL := os.Getenv("TEST_DB_URL")
+	if len(dbURL) == 0 {
+		t.Skip("TEST_DB_URL is not set")
+	}
+
+	admin, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	suffix := make([]byte, 8)
+	rand.Read(suffix)
+	schema := "test_" + hex.EncodeToString(suffix)
+	_, err = admin.Exec("CREATE SCHEMA " + schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Exec("DROP SCHEMA " + schema + " CASCADE") })
+
+	schemaURL, err := url.Parse(dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := schemaURL.Query()
+	query.Set("search_path", schema)
+	schemaURL.RawQuery = query.Encode()
+	dbConn, err := sql.Open("postgres", schemaURL.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dbConn.Close() })
+
+	migrations, err := filepath.Glob("sql/schema/*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, migration := range migrations {
+		data, err := os.ReadFile(migration)
+		if err != nil {
+			t.Fatal(err)
+		}
+		up, _, _ := strings.Cut(string(data), "-- +goose Down")
+		_, err = dbConn.Exec(up)
+		if err != nil {
+			t.Fatalf("%s: %v", migration, err)
+		}
+	}
+
+	return &apiConfig{
+		dbConn:      dbConn,
+		db:          database.New(dbConn),
+		filterMode:  filter.ModeMask,
+		viewCounter: views.NewCounter(),
+	}
+}
+
+// createTestUser adds a verified user with the given handle.
+func createTestUser(t *testing.T, cfg *apiConfig, handle string) database.User {
+	t.Helper()
+	user, err := cfg.db.CreateUser(context.Background(), database.CreateUserParams{
+		Email:          handle + "@example.com",
+		HashedPassword: "unused",
+		Handle:         handle,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = cfg.dbConn.Exec("UPDATE users SET email_verified = true WHERE id = $1", user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
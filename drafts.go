@@ -0,0 +1,486 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"servers/internal/database"
+	"servers/internal/pagination"
+
+	"github.com/google/uuid"
+)
+
+type Draft struct {
+	ID             uuid.UUID  `json:"id"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	UserID         uuid.UUID  `json:"user_id"`
+	Body           string     `json:"body"`
+	InReplyTo      *uuid.UUID `json:"in_reply_to"`
+	Visibility     string     `json:"visibility"`
+	ContentWarning *string    `json:"content_warning"`
+	Sensitive      bool       `json:"sensitive"`
+}
+
+type draftRequest struct {
+	Body           string     `json:"body"`
+	InReplyTo      *uuid.UUID `json:"in_reply_to"`
+	Visibility     string     `json:"visibility"`
+	ContentWarning *string    `json:"content_warning"`
+	Sensitive      bool       `json:"sensitive"`
+}
+
+func draftFromDB(draft database.Draft) Draft {
+	apiDraft := Draft{
+		ID:         draft.ID,
+		CreatedAt:  draft.CreatedAt,
+		UpdatedAt:  draft.UpdatedAt,
+		UserID:     draft.UserID,
+		Body:       draft.Body,
+		Visibility: draft.Visibility,
+		Sensitive:  draft.Sensitive,
+	}
+	if draft.ContentWarning.Valid {
+		contentWarning := draft.ContentWarning.String
+		apiDraft.ContentWarning = &contentWarning
+	}
+	if draft.ParentID.Valid {
+		parentID := draft.ParentID.UUID
+		apiDraft.InReplyTo = &parentID
+	}
+	return apiDraft
+}
+
+// draftParent checks that the chirp a draft replies to exists and is visible
+// to the user. A reply to a rechirp goes to the original. Apart from the
+// length limit, the body is not validated until the draft is published.
+func (cfg *apiConfig) draftParent(ctx context.Context, userID uuid.UUID, inReplyTo *uuid.UUID) (uuid.NullUUID, error) {
+	if inReplyTo == nil {
+		return uuid.NullUUID{}, nil
+	}
+	parent, err := cfg.db.GetChirp(ctx, database.GetChirpParams{ID: *inReplyTo, ViewerID: userID})
+	if err != nil {
+		return uuid.NullUUID{}, err
+	}
+	return uuid.NullUUID{UUID: originalID(parent), Valid: true}, nil
+}
+
+// publishDraft turns a draft into a chirp in a single transaction, so a
+// draft is published at most once even if the request is repeated. It
+// returns sql.ErrNoRows if the draft no longer exists.
+func (cfg *apiConfig) publishDraft(ctx context.Context, draft database.Draft, body string, flagged []string) (database.Chirp, error) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	_, err = qtx.DeleteDraft(ctx, database.DeleteDraftParams{ID: draft.ID, UserID: draft.UserID})
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	chirp, err := insertChirp(ctx, qtx, database.CreateChirpParams{
+		Body:           body,
+		UserID:         draft.UserID,
+		ParentID:       draft.ParentID,
+		Visibility:     draft.Visibility,
+		ContentWarning: draft.ContentWarning,
+		Sensitive:      draft.Sensitive,
+	}, flagged, nil, nil)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	return chirp, tx.Commit()
+}
+
+func (cfg *apiConfig) handlerCreateDraft(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	params := draftRequest{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Invalid request body"})
+		w.Write(data)
+		return
+	}
+	if len(params.Body) > maxChirpLength {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Chirp is too long"})
+		w.Write(data)
+		return
+	}
+
+	parentID, err := cfg.draftParent(r.Context(), userID, params.InReplyTo)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Chirp being replied to does not exist"})
+		w.Write(data)
+		return
+	}
+	visibility, err := validateVisibility(params.Visibility)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+	contentWarning, err := validateContentWarning(params.ContentWarning)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+
+	draft, err := cfg.db.CreateDraft(r.Context(), database.CreateDraftParams{
+		UserID:         userID,
+		Body:           params.Body,
+		ParentID:       parentID,
+		Visibility:     visibility,
+		ContentWarning: contentWarning,
+		Sensitive:      params.Sensitive,
+	})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	w.WriteHeader(201)
+	data, _ := json.Marshal(draftFromDB(draft))
+	w.Write(data)
+}
+
+func (cfg *apiConfig) handlerDrafts(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	type draftsResp struct {
+		Drafts     []Draft `json:"drafts"`
+		NextCursor string  `json:"next_cursor,omitempty"`
+		PrevCursor string  `json:"prev_cursor,omitempty"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	page, err := parsePageRequest(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+
+	cursorCreatedAt, cursorID := page.cursorArgs()
+	var drafts []database.Draft
+	if page.ascending() {
+		drafts, err = cfg.db.GetDraftsAscending(r.Context(), database.GetDraftsAscendingParams{
+			UserID:          userID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        page.rowLimit(),
+		})
+	} else {
+		drafts, err = cfg.db.GetDraftsDescending(r.Context(), database.GetDraftsDescendingParams{
+			UserID:          userID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        page.rowLimit(),
+		})
+	}
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	drafts, nextCursor, prevCursor := paginate(drafts, page, func(draft database.Draft) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: draft.CreatedAt, ID: draft.ID}
+	})
+	apiDrafts := make([]Draft, len(drafts))
+	for i, draft := range drafts {
+		apiDrafts[i] = draftFromDB(draft)
+	}
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(draftsResp{
+		Drafts:     apiDrafts,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	})
+	w.Write(data)
+}
+
+func (cfg *apiConfig) handlerGetDraft(w http.ResponseWriter, r *http.Request) {
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	draftID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Draft not found"))
+		return
+	}
+	draft, err := cfg.db.GetDraft(r.Context(), database.GetDraftParams{ID: draftID, UserID: userID})
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Draft not found"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	data, _ := json.Marshal(draftFromDB(draft))
+	w.Write(data)
+}
+
+func (cfg *apiConfig) handlerUpdateDraft(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	draftID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Draft not found"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	params := draftRequest{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Invalid request body"})
+		w.Write(data)
+		return
+	}
+	if len(params.Body) > maxChirpLength {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Chirp is too long"})
+		w.Write(data)
+		return
+	}
+
+	parentID, err := cfg.draftParent(r.Context(), userID, params.InReplyTo)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Chirp being replied to does not exist"})
+		w.Write(data)
+		return
+	}
+	visibility, err := validateVisibility(params.Visibility)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+	contentWarning, err := validateContentWarning(params.ContentWarning)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+
+	draft, err := cfg.db.UpdateDraft(r.Context(), database.UpdateDraftParams{
+		Body:           params.Body,
+		ParentID:       parentID,
+		Visibility:     visibility,
+		ContentWarning: contentWarning,
+		Sensitive:      params.Sensitive,
+		ID:             draftID,
+		UserID:         userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		w.WriteHeader(404)
+		w.Write([]byte("Draft not found"))
+		return
+	}
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(draftFromDB(draft))
+	w.Write(data)
+}
+
+func (cfg *apiConfig) handlerDeleteDraft(w http.ResponseWriter, r *http.Request) {
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	draftID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Draft not found"))
+		return
+	}
+	_, err = cfg.db.DeleteDraft(r.Context(), database.DeleteDraftParams{ID: draftID, UserID: userID})
+	if errors.Is(err, sql.ErrNoRows) {
+		w.WriteHeader(404)
+		w.Write([]byte("Draft not found"))
+		return
+	}
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerPublishDraft(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	draftID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Draft not found"))
+		return
+	}
+	draft, err := cfg.db.GetDraft(r.Context(), database.GetDraftParams{ID: draftID, UserID: userID})
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Draft not found"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	user, err := cfg.db.GetUser(r.Context(), userID)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	if !user.EmailVerified {
+		w.WriteHeader(403)
+		data, _ := json.Marshal(errorResp{Error: "Verify your email address before posting chirps"})
+		w.Write(data)
+		return
+	}
+
+	chirpFilter, err := cfg.chirpFilter(r.Context())
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	body, flagged, err := validateChirpBody(chirpFilter, draft.Body)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+
+	if draft.ParentID.Valid {
+		_, err = cfg.db.GetChirp(r.Context(), database.GetChirpParams{ID: draft.ParentID.UUID, ViewerID: userID})
+		if err != nil {
+			w.WriteHeader(400)
+			data, _ := json.Marshal(errorResp{Error: "Chirp being replied to does not exist"})
+			w.Write(data)
+			return
+		}
+	}
+
+	chirp, err := cfg.publishDraft(r.Context(), draft, body, flagged)
+	if errors.Is(err, sql.ErrNoRows) {
+		w.WriteHeader(404)
+		w.Write([]byte("Draft not found"))
+		return
+	}
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	chirpsToReturn, err := cfg.hydrateChirps(r.Context(), userID, []database.Chirp{chirp})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	w.WriteHeader(201)
+	data, _ := json.Marshal(chirpsToReturn[0])
+	w.Write(data)
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"servers/internal/database"
+	"servers/internal/pagination"
+
+	"github.com/google/uuid"
+)
+
+type Follow struct {
+	UserID     uuid.UUID `json:"user_id"`
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+func (cfg *apiConfig) handlerFollow(w http.ResponseWriter, r *http.Request) {
+	followerID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	followedID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("user not found"))
+		return
+	}
+
+	if followedID == followerID {
+		w.WriteHeader(400)
+		w.Write([]byte("you cannot follow yourself"))
+		return
+	}
+
+	_, err = cfg.db.GetUser(r.Context(), followedID)
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("user not found"))
+		return
+	}
+
+	err = cfg.db.FollowUser(r.Context(), database.FollowUserParams{FollowerID: followerID, FollowedID: followedID})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerUnfollow(w http.ResponseWriter, r *http.Request) {
+	followerID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	followedID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("user not found"))
+		return
+	}
+
+	err = cfg.db.UnfollowUser(r.Context(), database.UnfollowUserParams{FollowerID: followerID, FollowedID: followedID})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerFollowers(w http.ResponseWriter, r *http.Request) {
+	cfg.writeFollowPage(w, r, func(userID uuid.UUID, page pageRequest) ([]Follow, error) {
+		cursorCreatedAt, cursorID := page.cursorArgs()
+		var follows []Follow
+		if page.ascending() {
+			rows, err := cfg.db.GetFollowersAscending(r.Context(), database.GetFollowersAscendingParams{
+				UserID:          userID,
+				CursorCreatedAt: cursorCreatedAt,
+				CursorID:        cursorID,
+				RowLimit:        page.rowLimit(),
+			})
+			for _, row := range rows {
+				follows = append(follows, Follow{UserID: row.UserID, FollowedAt: row.CreatedAt})
+			}
+			return follows, err
+		}
+		rows, err := cfg.db.GetFollowersDescending(r.Context(), database.GetFollowersDescendingParams{
+			UserID:          userID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        page.rowLimit(),
+		})
+		for _, row := range rows {
+			follows = append(follows, Follow{UserID: row.UserID, FollowedAt: row.CreatedAt})
+		}
+		return follows, err
+	})
+}
+
+func (cfg *apiConfig) handlerFollowing(w http.ResponseWriter, r *http.Request) {
+	cfg.writeFollowPage(w, r, func(userID uuid.UUID, page pageRequest) ([]Follow, error) {
+		cursorCreatedAt, cursorID := page.cursorArgs()
+		var follows []Follow
+		if page.ascending() {
+			rows, err := cfg.db.GetFollowingAscending(r.Context(), database.GetFollowingAscendingParams{
+				UserID:          userID,
+				CursorCreatedAt: cursorCreatedAt,
+				CursorID:        cursorID,
+				RowLimit:        page.rowLimit(),
+			})
+			for _, row := range rows {
+				follows = append(follows, Follow{UserID: row.UserID, FollowedAt: row.CreatedAt})
+			}
+			return follows, err
+		}
+		rows, err := cfg.db.GetFollowingDescending(r.Context(), database.GetFollowingDescendingParams{
+			UserID:          userID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        page.rowLimit(),
+		})
+		for _, row := range rows {
+			follows = append(follows, Follow{UserID: row.UserID, FollowedAt: row.CreatedAt})
+		}
+		return follows, err
+	})
+}
+
+// writeFollowPage handles the parts shared by the follower and following
+// listings; fetch runs the direction-specific query for the user in the path.
+func (cfg *apiConfig) writeFollowPage(w http.ResponseWriter, r *http.Request, fetch func(uuid.UUID, pageRequest) ([]Follow, error)) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	type followsResp struct {
+		Users      []Follow `json:"users"`
+		NextCursor string   `json:"next_cursor,omitempty"`
+		PrevCursor string   `json:"prev_cursor,omitempty"`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	userID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		data, _ := json.Marshal(errorResp{Error: "user not found"})
+		w.Write(data)
+		return
+	}
+
+	page, err := parsePageRequest(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+
+	follows, err := fetch(userID, page)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	follows, nextCursor, prevCursor := paginate(follows, page, func(follow Follow) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: follow.FollowedAt, ID: follow.UserID}
+	})
+	if follows == nil {
+		follows = []Follow{}
+	}
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(followsResp{
+		Users:      follows,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	})
+	w.Write(data)
+}
@@ -2,9 +2,9 @@ package auth
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
-	"log"
 	"net/http"
 	"strings"
 	"time"
@@ -31,7 +31,6 @@ func CheckPasswordHash(hash, password string) error {
 }
 
 func MakeJWT(userID uuid.UUID, tokenSecret string, expiresIn time.Duration) (string, error) {
-	log.Printf("expiry: %v", expiresIn)
 	now := time.Now().UTC()
 	claims := &jwt.RegisteredClaims{
 		ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)),
@@ -51,6 +50,9 @@ type CustomClaims struct {
 	jwt.RegisteredClaims
 }
 
+// ValidateJWT returns the user an access token made by MakeJWT was issued
+// to. Tokens from MakeEmailToken and MakeChallengeToken are signed with the
+// same secret but have a different issuer, so they are rejected here.
 func ValidateJWT(tokenString, tokenSecret string) (uuid.UUID, error) {
 	claims := &CustomClaims{}
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
@@ -58,7 +60,7 @@ func ValidateJWT(tokenString, tokenSecret string) (uuid.UUID, error) {
 			return nil, fmt.Errorf("invalid signing method: %v", token.Header["alg"])
 		}
 		return []byte(tokenSecret), nil
-	})
+	}, jwt.WithIssuer("chirpy"))
 	if err != nil {
 		return uuid.Nil, err
 	}
@@ -73,14 +75,86 @@ func ValidateJWT(tokenString, tokenSecret string) (uuid.UUID, error) {
 	return uuid_, nil
 }
 
+// MakeEmailToken signs a token that is sent to a user by email for purpose,
+// such as confirming their address. id identifies the token so that the
+// caller can make sure it is only used once.
+func MakeEmailToken(id uuid.UUID, purpose, tokenSecret string, expiresIn time.Duration) (string, error) {
+	now := time.Now().UTC()
+	claims := &jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)),
+		Issuer:    "chirpy-email",
+		Audience:  jwt.ClaimStrings{purpose},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ID:        id.String(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(tokenSecret))
+}
+
+// ValidateEmailToken returns the ID of a token made by MakeEmailToken for
+// purpose.
+func ValidateEmailToken(tokenString, purpose, tokenSecret string) (uuid.UUID, error) {
+	claims := &CustomClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("invalid signing method: %v", token.Header["alg"])
+		}
+		return []byte(tokenSecret), nil
+	}, jwt.WithIssuer("chirpy-email"), jwt.WithAudience(purpose), jwt.WithExpirationRequired())
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return uuid.Parse(claims.ID)
+}
+
+// MakeChallengeToken signs a token proving that userID has given the right
+// password, for completing a login with their second factor. It cannot be
+// used as an access token. id identifies the challenge so that the caller can
+// limit attempts and make sure it is only used once.
+func MakeChallengeToken(id, userID uuid.UUID, tokenSecret string, expiresIn time.Duration) (string, error) {
+	now := time.Now().UTC()
+	claims := &jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)),
+		Issuer:    "chirpy-2fa",
+		IssuedAt:  jwt.NewNumericDate(now),
+		Subject:   userID.String(),
+		ID:        id.String(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(tokenSecret))
+}
+
+// ValidateChallengeToken returns the ID of a token made by MakeChallengeToken
+// and the user it was issued to.
+func ValidateChallengeToken(tokenString, tokenSecret string) (uuid.UUID, uuid.UUID, error) {
+	claims := &CustomClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("invalid signing method: %v", token.Header["alg"])
+		}
+		return []byte(tokenSecret), nil
+	}, jwt.WithIssuer("chirpy-2fa"), jwt.WithExpirationRequired())
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	id, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return id, userID, nil
+}
+
 func GetBearerToken(headers http.Header) (string, error) {
 	completeTokenString := headers.Get("Authorization")
-	log.Printf("token string %v", completeTokenString)
 	if len(completeTokenString) == 0 {
 		return "", fmt.Errorf("token invalid")
 	}
-	tokenString := strings.Split(completeTokenString, " ")[1]
-	if len(tokenString) == 0 {
+	_, tokenString, found := strings.Cut(completeTokenString, " ")
+	if !found || len(tokenString) == 0 {
 		return "", fmt.Errorf("token invalid")
 	}
 	return tokenString, nil
@@ -91,11 +165,10 @@ func GetAPIKey(header http.Header) (string, error) {
 	if len(completeAPIKeyString) == 0 {
 		return "", fmt.Errorf("missing api key")
 	}
-	APIString := strings.Split(completeAPIKeyString, " ")[1]
-	if len(APIString) == 0 {
+	_, APIString, found := strings.Cut(completeAPIKeyString, " ")
+	if !found || len(APIString) == 0 {
 		return "", fmt.Errorf("invalid api key")
 	}
-	log.Printf("%v", APIString)
 	return APIString, nil
 }
 
@@ -109,3 +182,10 @@ func MakeRefreshToken() (string, error) {
 	encodedString := hex.EncodeToString(randomBytes)
 	return encodedString, nil
 }
+
+// HashToken returns the SHA-256 hash of a random token, for storing tokens
+// that should not be usable by anyone who can read the database.
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -23,6 +23,60 @@ func TestMakeJWT(t *testing.T) {
 	}
 }
 
+func TestEmailToken(t *testing.T) {
+	id := uuid.New()
+	secret := "secret"
+
+	token, err := MakeEmailToken(id, "verify_email", secret, time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to make email token: %v", err)
+	}
+	got, err := ValidateEmailToken(token, "verify_email", secret)
+	if err != nil || got != id {
+		t.Fatalf("ValidateEmailToken() = %v, %v, expected %v", got, err, id)
+	}
+
+	if _, err := ValidateEmailToken(token, "reset_password", secret); err == nil {
+		t.Fatalf("Expected token for another purpose to be rejected")
+	}
+	if _, err := ValidateEmailToken(token, "verify_email", "other secret"); err == nil {
+		t.Fatalf("Expected token signed with another secret to be rejected")
+	}
+	if _, err := ValidateJWT(token, secret); err == nil {
+		t.Fatalf("Expected email token to be rejected as an access token")
+	}
+
+	expired, _ := MakeEmailToken(id, "verify_email", secret, -time.Minute)
+	if _, err := ValidateEmailToken(expired, "verify_email", secret); err == nil {
+		t.Fatalf("Expected expired token to be rejected")
+	}
+}
+
+func TestValidateJWT(t *testing.T) {
+	userID := uuid.New()
+	token, err := MakeJWT(userID, "secret", time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to generate jwt: %v", err)
+	}
+	got, err := ValidateJWT(token, "secret")
+	if err != nil || got != userID {
+		t.Fatalf("ValidateJWT() = %v, %v, expected %v", got, err, userID)
+	}
+}
+
+func TestHashToken(t *testing.T) {
+	token, err := MakeRefreshToken()
+	if err != nil {
+		t.Fatalf("Failed to make token: %v", err)
+	}
+	if HashToken(token) != HashToken(token) {
+		t.Fatalf("Expected hashing to be deterministic")
+	}
+	if HashToken(token) == token || HashToken(token) == HashToken(token+"0") {
+		t.Fatalf("Expected hash to differ from the token and other tokens")
+	}
+}
+
 func testGetBearerToken(t *testing.T) {
 	testString := "Bearer djfliwsefhsdlkdfhlsdkjf"
 	headers := http.Header{}
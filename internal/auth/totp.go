@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP codes are the six digit, 30 second, HMAC-SHA1 variant of RFC 6238
+// that every authenticator app supports.
+const (
+	totpDigits = 6
+	totpPeriod = 30 * time.Second
+	// totpSkew is how many periods either side of now a code is accepted for,
+	// to allow for clock drift and slow typing.
+	totpSkew = 1
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// MakeTOTPSecret returns a random base32 encoded secret for enrolling an
+// authenticator.
+func MakeTOTPSecret() (string, error) {
+	secret := make([]byte, 20)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", fmt.Errorf("error in creating totp secret: %v", err)
+	}
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// TOTPURI returns the otpauth:// URI that authenticator apps scan to enroll
+// secret for account.
+func TOTPURI(secret, issuer, account string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(int(totpPeriod.Seconds())))
+	return "otpauth://totp/" + url.PathEscape(issuer+":"+account) + "?" + query.Encode()
+}
+
+// TOTPStep returns the number of the time step t falls in.
+func TOTPStep(t time.Time) int64 {
+	return t.Unix() / int64(totpPeriod.Seconds())
+}
+
+// GenerateTOTP returns the code for secret at time t.
+func GenerateTOTP(secret string, t time.Time) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+	return hotp(key, uint64(TOTPStep(t)), totpDigits), nil
+}
+
+// ValidateTOTP checks code against secret at time t and returns the time step
+// it matched. Callers should remember the step and refuse codes from it or
+// earlier steps, so that an intercepted code cannot be replayed.
+func ValidateTOTP(secret, code string, t time.Time) (int64, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, err
+	}
+	code = strings.TrimSpace(code)
+	now := TOTPStep(t)
+	for step := now - totpSkew; step <= now+totpSkew; step++ {
+		if hmac.Equal([]byte(hotp(key, uint64(step), totpDigits)), []byte(code)) {
+			return step, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid code")
+}
+
+// hotp computes an RFC 4226 one-time password.
+func hotp(key []byte, counter uint64, digits int) string {
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, counter)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	modulus := uint32(1)
+	for i := 0; i < digits; i++ {
+		modulus *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%modulus)
+}
+
+// MakeRecoveryCode returns a random single-use code for getting past
+// two-factor authentication without the authenticator. Like other tokens it
+// should only be stored as HashToken(NormalizeRecoveryCode(code)).
+func MakeRecoveryCode() (string, error) {
+	randomBytes := make([]byte, 8)
+	_, err := rand.Read(randomBytes)
+	if err != nil {
+		return "", fmt.Errorf("error in creating recovery code: %v", err)
+	}
+	encoded := hex.EncodeToString(randomBytes)
+	return encoded[:4] + "-" + encoded[4:8] + "-" + encoded[8:12] + "-" + encoded[12:], nil
+}
+
+// NormalizeRecoveryCode strips the formatting a user may or may not have
+// typed from a recovery code.
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestHOTPRFC6238(t *testing.T) {
+	// the SHA1 test vectors from RFC 6238 appendix B
+	key := []byte("12345678901234567890")
+	cases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	for _, c := range cases {
+		got := hotp(key, uint64(TOTPStep(time.Unix(c.unix, 0))), 8)
+		if got != c.code {
+			t.Errorf("hotp at %d = %s, expected %s", c.unix, got, c.code)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret := totpEncoding.EncodeToString([]byte("12345678901234567890"))
+	at := time.Unix(1111111109, 0)
+
+	code, err := GenerateTOTP(secret, at)
+	if err != nil || code != "081804" {
+		t.Fatalf("GenerateTOTP() = %v, %v, expected 081804", code, err)
+	}
+
+	step, err := ValidateTOTP(secret, code, at.Add(totpPeriod))
+	if err != nil || step != TOTPStep(at) {
+		t.Fatalf("ValidateTOTP() = %v, %v, expected step %v", step, err, TOTPStep(at))
+	}
+	if _, err := ValidateTOTP(secret, code, at.Add(3*totpPeriod)); err == nil {
+		t.Fatalf("Expected stale code to be rejected")
+	}
+	if _, err := ValidateTOTP(secret, "000000", at); err == nil {
+		t.Fatalf("Expected wrong code to be rejected")
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	secret, err := MakeTOTPSecret()
+	if err != nil {
+		t.Fatalf("Failed to make secret: %v", err)
+	}
+	uri := TOTPURI(secret, "Chirpy", "jane")
+	if !strings.HasPrefix(uri, "otpauth://totp/Chirpy:jane?") || !strings.Contains(uri, "secret="+secret) {
+		t.Fatalf("Unexpected uri %s", uri)
+	}
+}
+
+func TestRecoveryCode(t *testing.T) {
+	code, err := MakeRecoveryCode()
+	if err != nil {
+		t.Fatalf("Failed to make recovery code: %v", err)
+	}
+	if len(code) != 19 {
+		t.Fatalf("Unexpected recovery code %s", code)
+	}
+	if NormalizeRecoveryCode(" "+strings.ToUpper(code)) != strings.ReplaceAll(code, "-", "") {
+		t.Fatalf("Expected formatting to be ignored")
+	}
+}
+
+func TestChallengeToken(t *testing.T) {
+	id := uuid.New()
+	userID := uuid.New()
+	token, err := MakeChallengeToken(id, userID, "secret", time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to make challenge token: %v", err)
+	}
+	gotID, gotUserID, err := ValidateChallengeToken(token, "secret")
+	if err != nil || gotID != id || gotUserID != userID {
+		t.Fatalf("ValidateChallengeToken() = %v, %v, %v, expected %v, %v", gotID, gotUserID, err, id, userID)
+	}
+	if _, err := ValidateJWT(token, "secret"); err == nil {
+		t.Fatalf("Expected challenge token to be rejected as an access token")
+	}
+
+	access, _ := MakeJWT(userID, "secret", time.Minute)
+	if _, _, err := ValidateChallengeToken(access, "secret"); err == nil {
+		t.Fatalf("Expected access token to be rejected as a challenge token")
+	}
+}
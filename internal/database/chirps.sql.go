@@ -7,29 +7,44 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 const createChirp = `-- name: CreateChirp :one
-INSERT INTO chirps (id, created_at, updated_at, body, user_id)
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, parent_id, publish_at, quote_of, content_warning, sensitive, visibility)
 VALUES (
 gen_random_uuid(),
 NOW(),
 NOW(),
 $1,
-$2
+$2,
+$3,
+$4,
+$5,
+$6,
+$7,
+$8
 )
-RETURNING id, created_at, updated_at, body, user_id
+RETURNING id, created_at, updated_at, body, user_id, parent_id, search_vector, deleted_at, publish_at, rechirp_of, quote_of, content_warning, sensitive, visibility
 `
 
 type CreateChirpParams struct {
-	Body   string
-	UserID uuid.UUID
+	Body           string
+	UserID         uuid.UUID
+	ParentID       uuid.NullUUID
+	PublishAt      sql.NullTime
+	QuoteOf        uuid.NullUUID
+	ContentWarning sql.NullString
+	Sensitive      bool
+	Visibility     string
 }
 
 func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp, error) {
-	row := q.db.QueryRowContext(ctx, createChirp, arg.Body, arg.UserID)
+	row := q.db.QueryRowContext(ctx, createChirp, arg.Body, arg.UserID, arg.ParentID, arg.PublishAt, arg.QuoteOf, arg.ContentWarning, arg.Sensitive, arg.Visibility)
 	var i Chirp
 	err := row.Scan(
 		&i.ID,
@@ -37,12 +52,55 @@ func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.ParentID,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.PublishAt,
+		&i.RechirpOf,
+		&i.QuoteOf,
+		&i.ContentWarning,
+		&i.Sensitive,
+		&i.Visibility,
+	)
+	return i, err
+}
+
+const createRechirp = `-- name: CreateRechirp :one
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, rechirp_of)
+VALUES (gen_random_uuid(), NOW(), NOW(), '', $1, $2)
+ON CONFLICT (user_id, rechirp_of) WHERE rechirp_of IS NOT NULL AND deleted_at IS NULL DO NOTHING
+RETURNING id, created_at, updated_at, body, user_id, parent_id, search_vector, deleted_at, publish_at, rechirp_of, quote_of, content_warning, sensitive, visibility
+`
+
+type CreateRechirpParams struct {
+	UserID    uuid.UUID
+	RechirpOf uuid.NullUUID
+}
+
+func (q *Queries) CreateRechirp(ctx context.Context, arg CreateRechirpParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, createRechirp, arg.UserID, arg.RechirpOf)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.ParentID,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.PublishAt,
+		&i.RechirpOf,
+		&i.QuoteOf,
+		&i.ContentWarning,
+		&i.Sensitive,
+		&i.Visibility,
 	)
 	return i, err
 }
 
 const deleteChirp = `-- name: DeleteChirp :exec
-DELETE FROM chirps WHERE id = $1
+UPDATE chirps SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) DeleteChirp(ctx context.Context, id uuid.UUID) error {
@@ -50,12 +108,248 @@ func (q *Queries) DeleteChirp(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const deleteRechirp = `-- name: DeleteRechirp :execrows
+UPDATE chirps SET deleted_at = NOW() WHERE user_id = $1 AND rechirp_of = $2 AND deleted_at IS NULL
+`
+
+type DeleteRechirpParams struct {
+	UserID    uuid.UUID
+	RechirpOf uuid.NullUUID
+}
+
+func (q *Queries) DeleteRechirp(ctx context.Context, arg DeleteRechirpParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteRechirp, arg.UserID, arg.RechirpOf)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getChirp = `-- name: GetChirp :one
-SELECT id, created_at, updated_at, body, user_id FROM chirps WHERE id = $1
+SELECT id, created_at, updated_at, body, user_id, parent_id, search_vector, deleted_at, publish_at, rechirp_of, quote_of, content_warning, sensitive, visibility FROM chirps
+WHERE id = $1 AND deleted_at IS NULL AND publish_at IS NULL
+AND chirp_visible_to(id, user_id, visibility, $2::uuid)
+`
+
+type GetChirpParams struct {
+	ID       uuid.UUID
+	ViewerID uuid.UUID
+}
+
+func (q *Queries) GetChirp(ctx context.Context, arg GetChirpParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, getChirp, arg.ID, arg.ViewerID)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.ParentID,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.PublishAt,
+		&i.RechirpOf,
+		&i.QuoteOf,
+		&i.ContentWarning,
+		&i.Sensitive,
+		&i.Visibility,
+	)
+	return i, err
+}
+
+const getChirpAncestors = `-- name: GetChirpAncestors :many
+WITH RECURSIVE ancestors AS (
+  SELECT id, created_at, updated_at, body, user_id, parent_id, search_vector, deleted_at, publish_at, rechirp_of, quote_of, content_warning, sensitive, visibility, 1 AS depth
+  FROM chirps
+  WHERE id = (SELECT parent_id FROM chirps WHERE chirps.id = $1)
+  UNION ALL
+  SELECT c.id, c.created_at, c.updated_at, c.body, c.user_id, c.parent_id, c.search_vector, c.deleted_at, c.publish_at, c.rechirp_of, c.quote_of, c.content_warning, c.sensitive, c.visibility, a.depth + 1
+  FROM chirps c
+  JOIN ancestors a ON c.id = a.parent_id
+)
+SELECT id, created_at, updated_at, body, user_id, parent_id, search_vector, deleted_at, publish_at, rechirp_of, quote_of, content_warning, sensitive, visibility FROM ancestors
+WHERE deleted_at IS NULL AND publish_at IS NULL
+AND chirp_visible_to(id, user_id, visibility, $2::uuid)
+ORDER BY depth DESC
+`
+
+type GetChirpAncestorsParams struct {
+	ID       uuid.UUID
+	ViewerID uuid.UUID
+}
+
+func (q *Queries) GetChirpAncestors(ctx context.Context, arg GetChirpAncestorsParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpAncestors, arg.ID, arg.ViewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ParentID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.PublishAt,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.ContentWarning,
+			&i.Sensitive,
+			&i.Visibility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getChirpDescendants = `-- name: GetChirpDescendants :many
+WITH RECURSIVE descendants AS (
+  SELECT id, created_at, updated_at, body, user_id, parent_id, deleted_at, publish_at, quote_of, content_warning, sensitive, visibility, 1 AS depth
+  FROM chirps
+  WHERE chirps.parent_id = $1::uuid
+  UNION ALL
+  SELECT c.id, c.created_at, c.updated_at, c.body, c.user_id, c.parent_id, c.deleted_at, c.publish_at, c.quote_of, c.content_warning, c.sensitive, c.visibility, d.depth + 1
+  FROM chirps c
+  JOIN descendants d ON c.parent_id = d.id
+)
+SELECT id, created_at, updated_at, body, user_id, parent_id, quote_of, content_warning, sensitive, visibility, depth FROM descendants
+WHERE deleted_at IS NULL AND publish_at IS NULL
+AND chirp_visible_to(id, user_id, visibility, $2::uuid)
+AND ($3::timestamp IS NULL OR (created_at, id) > ($3::timestamp, $4::uuid))
+ORDER BY created_at ASC, id ASC
+LIMIT $5
+`
+
+type GetChirpDescendantsParams struct {
+	RootID          uuid.UUID
+	ViewerID        uuid.UUID
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	RowLimit        int32
+}
+
+type GetChirpDescendantsRow struct {
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Body           string
+	UserID         uuid.UUID
+	ParentID       uuid.NullUUID
+	QuoteOf        uuid.NullUUID
+	ContentWarning sql.NullString
+	Sensitive      bool
+	Visibility     string
+	Depth          int32
+}
+
+func (q *Queries) GetChirpDescendants(ctx context.Context, arg GetChirpDescendantsParams) ([]GetChirpDescendantsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpDescendants, arg.RootID, arg.ViewerID, arg.CursorCreatedAt, arg.CursorID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetChirpDescendantsRow
+	for rows.Next() {
+		var i GetChirpDescendantsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ParentID,
+			&i.QuoteOf,
+			&i.ContentWarning,
+			&i.Sensitive,
+			&i.Visibility,
+			&i.Depth,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getChirpForUpdate = `-- name: GetChirpForUpdate :one
+SELECT id, created_at, updated_at, body, user_id, parent_id, search_vector, deleted_at, publish_at, rechirp_of, quote_of, content_warning, sensitive, visibility FROM chirps WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+`
+
+func (q *Queries) GetChirpForUpdate(ctx context.Context, id uuid.UUID) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, getChirpForUpdate, id)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.ParentID,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.PublishAt,
+		&i.RechirpOf,
+		&i.QuoteOf,
+		&i.ContentWarning,
+		&i.Sensitive,
+		&i.Visibility,
+	)
+	return i, err
+}
+
+const getChirpIncludingDeleted = `-- name: GetChirpIncludingDeleted :one
+SELECT id, created_at, updated_at, body, user_id, parent_id, search_vector, deleted_at, publish_at, rechirp_of, quote_of, content_warning, sensitive, visibility FROM chirps WHERE id = $1
+`
+
+func (q *Queries) GetChirpIncludingDeleted(ctx context.Context, id uuid.UUID) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, getChirpIncludingDeleted, id)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.ParentID,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.PublishAt,
+		&i.RechirpOf,
+		&i.QuoteOf,
+		&i.ContentWarning,
+		&i.Sensitive,
+		&i.Visibility,
+	)
+	return i, err
+}
+
+const getChirpIncludingScheduled = `-- name: GetChirpIncludingScheduled :one
+SELECT id, created_at, updated_at, body, user_id, parent_id, search_vector, deleted_at, publish_at, rechirp_of, quote_of, content_warning, sensitive, visibility FROM chirps WHERE id = $1 AND deleted_at IS NULL
 `
 
-func (q *Queries) GetChirp(ctx context.Context, id uuid.UUID) (Chirp, error) {
-	row := q.db.QueryRowContext(ctx, getChirp, id)
+func (q *Queries) GetChirpIncludingScheduled(ctx context.Context, id uuid.UUID) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, getChirpIncludingScheduled, id)
 	var i Chirp
 	err := row.Scan(
 		&i.ID,
@@ -63,17 +357,152 @@ func (q *Queries) GetChirp(ctx context.Context, id uuid.UUID) (Chirp, error) {
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.ParentID,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.PublishAt,
+		&i.RechirpOf,
+		&i.QuoteOf,
+		&i.ContentWarning,
+		&i.Sensitive,
+		&i.Visibility,
 	)
 	return i, err
 }
 
-const getChirps = `-- name: GetChirps :many
-SELECT id, created_at, updated_at, body, user_id FROM chirps 
-WHERE ($1 = CAST('00000000-0000-0000-0000-000000000000' as uuid) OR user_id = $1)
+const getChirpsAscending = `-- name: GetChirpsAscending :many
+SELECT chirps.id, chirps.created_at, chirps.updated_at, chirps.body, chirps.user_id, chirps.parent_id, chirps.search_vector, chirps.deleted_at, chirps.publish_at, chirps.rechirp_of, chirps.quote_of, chirps.content_warning, chirps.sensitive, chirps.visibility FROM chirps
+LEFT JOIN chirps original ON original.id = chirps.rechirp_of
+WHERE chirps.deleted_at IS NULL AND chirps.publish_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, $1::uuid)
+AND (chirps.rechirp_of IS NULL OR original.deleted_at IS NULL)
+AND (NOT $2::boolean OR (NOT COALESCE(original.sensitive, chirps.sensitive) AND COALESCE(original.content_warning, chirps.content_warning) IS NULL))
+AND ($3::uuid = CAST('00000000-0000-0000-0000-000000000000' as uuid) OR (chirps.user_id = $3::uuid AND NOT EXISTS (SELECT 1 FROM pins WHERE pins.chirp_id = chirps.id)))
+AND ($4::timestamp IS NULL OR (chirps.created_at, chirps.id) > ($4::timestamp, $5::uuid))
+ORDER BY chirps.created_at ASC, chirps.id ASC
+LIMIT $6
 `
 
-func (q *Queries) GetChirps(ctx context.Context, dollar_1 interface{}) ([]Chirp, error) {
-	rows, err := q.db.QueryContext(ctx, getChirps, dollar_1)
+type GetChirpsAscendingParams struct {
+	ViewerID        uuid.UUID
+	HideSensitive   bool
+	AuthorID        uuid.UUID
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	RowLimit        int32
+}
+
+func (q *Queries) GetChirpsAscending(ctx context.Context, arg GetChirpsAscendingParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpsAscending, arg.ViewerID, arg.HideSensitive, arg.AuthorID, arg.CursorCreatedAt, arg.CursorID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ParentID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.PublishAt,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.ContentWarning,
+			&i.Sensitive,
+			&i.Visibility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getChirpsByIDs = `-- name: GetChirpsByIDs :many
+SELECT id, created_at, updated_at, body, user_id, parent_id, search_vector, deleted_at, publish_at, rechirp_of, quote_of, content_warning, sensitive, visibility FROM chirps
+WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL AND publish_at IS NULL
+AND chirp_visible_to(id, user_id, visibility, $2::uuid)
+`
+
+type GetChirpsByIDsParams struct {
+	Ids      []uuid.UUID
+	ViewerID uuid.UUID
+}
+
+func (q *Queries) GetChirpsByIDs(ctx context.Context, arg GetChirpsByIDsParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpsByIDs, pq.Array(arg.Ids), arg.ViewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ParentID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.PublishAt,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.ContentWarning,
+			&i.Sensitive,
+			&i.Visibility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getChirpsDescending = `-- name: GetChirpsDescending :many
+SELECT chirps.id, chirps.created_at, chirps.updated_at, chirps.body, chirps.user_id, chirps.parent_id, chirps.search_vector, chirps.deleted_at, chirps.publish_at, chirps.rechirp_of, chirps.quote_of, chirps.content_warning, chirps.sensitive, chirps.visibility FROM chirps
+LEFT JOIN chirps original ON original.id = chirps.rechirp_of
+WHERE chirps.deleted_at IS NULL AND chirps.publish_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, $1::uuid)
+AND (chirps.rechirp_of IS NULL OR original.deleted_at IS NULL)
+AND (NOT $2::boolean OR (NOT COALESCE(original.sensitive, chirps.sensitive) AND COALESCE(original.content_warning, chirps.content_warning) IS NULL))
+AND ($3::uuid = CAST('00000000-0000-0000-0000-000000000000' as uuid) OR (chirps.user_id = $3::uuid AND NOT EXISTS (SELECT 1 FROM pins WHERE pins.chirp_id = chirps.id)))
+AND ($4::timestamp IS NULL OR (chirps.created_at, chirps.id) < ($4::timestamp, $5::uuid))
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT $6
+`
+
+type GetChirpsDescendingParams struct {
+	ViewerID        uuid.UUID
+	HideSensitive   bool
+	AuthorID        uuid.UUID
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	RowLimit        int32
+}
+
+func (q *Queries) GetChirpsDescending(ctx context.Context, arg GetChirpsDescendingParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpsDescending, arg.ViewerID, arg.HideSensitive, arg.AuthorID, arg.CursorCreatedAt, arg.CursorID, arg.RowLimit)
 	if err != nil {
 		return nil, err
 	}
@@ -87,6 +516,15 @@ func (q *Queries) GetChirps(ctx context.Context, dollar_1 interface{}) ([]Chirp,
 			&i.UpdatedAt,
 			&i.Body,
 			&i.UserID,
+			&i.ParentID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.PublishAt,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.ContentWarning,
+			&i.Sensitive,
+			&i.Visibility,
 		); err != nil {
 			return nil, err
 		}
@@ -100,3 +538,782 @@ func (q *Queries) GetChirps(ctx context.Context, dollar_1 interface{}) ([]Chirp,
 	}
 	return items, nil
 }
+
+const getRechirpCounts = `-- name: GetRechirpCounts :many
+SELECT rechirp_of, COUNT(*) AS rechirp_count FROM chirps
+WHERE rechirp_of = ANY($1::uuid[]) AND deleted_at IS NULL
+GROUP BY rechirp_of
+`
+
+type GetRechirpCountsRow struct {
+	RechirpOf    uuid.NullUUID
+	RechirpCount int64
+}
+
+func (q *Queries) GetRechirpCounts(ctx context.Context, ids []uuid.UUID) ([]GetRechirpCountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRechirpCounts, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRechirpCountsRow
+	for rows.Next() {
+		var i GetRechirpCountsRow
+		if err := rows.Scan(
+			&i.RechirpOf,
+			&i.RechirpCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReplyCounts = `-- name: GetReplyCounts :many
+SELECT parent_id, COUNT(*) AS reply_count FROM chirps
+WHERE parent_id = ANY($1::uuid[]) AND deleted_at IS NULL AND publish_at IS NULL
+AND chirp_visible_to(id, user_id, visibility, $2::uuid)
+GROUP BY parent_id
+`
+
+type GetReplyCountsParams struct {
+	Ids      []uuid.UUID
+	ViewerID uuid.UUID
+}
+
+type GetReplyCountsRow struct {
+	ParentID   uuid.NullUUID
+	ReplyCount int64
+}
+
+func (q *Queries) GetReplyCounts(ctx context.Context, arg GetReplyCountsParams) ([]GetReplyCountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getReplyCounts, pq.Array(arg.Ids), arg.ViewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReplyCountsRow
+	for rows.Next() {
+		var i GetReplyCountsRow
+		if err := rows.Scan(
+			&i.ParentID,
+			&i.ReplyCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getScheduledChirpsAscending = `-- name: GetScheduledChirpsAscending :many
+SELECT id, created_at, updated_at, body, user_id, parent_id, search_vector, deleted_at, publish_at, rechirp_of, quote_of, content_warning, sensitive, visibility FROM chirps
+WHERE user_id = $1
+AND deleted_at IS NULL AND publish_at IS NOT NULL
+AND ($2::timestamp IS NULL OR (publish_at, id) > ($2::timestamp, $3::uuid))
+ORDER BY publish_at ASC, id ASC
+LIMIT $4
+`
+
+type GetScheduledChirpsAscendingParams struct {
+	UserID          uuid.UUID
+	CursorPublishAt sql.NullTime
+	CursorID        uuid.NullUUID
+	RowLimit        int32
+}
+
+func (q *Queries) GetScheduledChirpsAscending(ctx context.Context, arg GetScheduledChirpsAscendingParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getScheduledChirpsAscending, arg.UserID, arg.CursorPublishAt, arg.CursorID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ParentID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.PublishAt,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.ContentWarning,
+			&i.Sensitive,
+			&i.Visibility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getScheduledChirpsDescending = `-- name: GetScheduledChirpsDescending :many
+SELECT id, created_at, updated_at, body, user_id, parent_id, search_vector, deleted_at, publish_at, rechirp_of, quote_of, content_warning, sensitive, visibility FROM chirps
+WHERE user_id = $1
+AND deleted_at IS NULL AND publish_at IS NOT NULL
+AND ($2::timestamp IS NULL OR (publish_at, id) < ($2::timestamp, $3::uuid))
+ORDER BY publish_at DESC, id DESC
+LIMIT $4
+`
+
+type GetScheduledChirpsDescendingParams struct {
+	UserID          uuid.UUID
+	CursorPublishAt sql.NullTime
+	CursorID        uuid.NullUUID
+	RowLimit        int32
+}
+
+func (q *Queries) GetScheduledChirpsDescending(ctx context.Context, arg GetScheduledChirpsDescendingParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getScheduledChirpsDescending, arg.UserID, arg.CursorPublishAt, arg.CursorID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ParentID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.PublishAt,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.ContentWarning,
+			&i.Sensitive,
+			&i.Visibility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTagChirpsAscending = `-- name: GetTagChirpsAscending :many
+SELECT chirps.id, chirps.created_at, chirps.updated_at, chirps.body, chirps.user_id, chirps.parent_id, chirps.search_vector, chirps.deleted_at, chirps.publish_at, chirps.rechirp_of, chirps.quote_of, chirps.content_warning, chirps.sensitive, chirps.visibility FROM chirps
+JOIN chirp_tags ON chirp_tags.chirp_id = chirps.id
+JOIN tags ON tags.id = chirp_tags.tag_id
+WHERE tags.name = $1
+AND chirps.deleted_at IS NULL AND chirps.publish_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, $2::uuid)
+AND ($3::timestamp IS NULL OR (chirps.created_at, chirps.id) > ($3::timestamp, $4::uuid))
+ORDER BY chirps.created_at ASC, chirps.id ASC
+LIMIT $5
+`
+
+type GetTagChirpsAscendingParams struct {
+	Tag             string
+	ViewerID        uuid.UUID
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	RowLimit        int32
+}
+
+func (q *Queries) GetTagChirpsAscending(ctx context.Context, arg GetTagChirpsAscendingParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getTagChirpsAscending, arg.Tag, arg.ViewerID, arg.CursorCreatedAt, arg.CursorID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ParentID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.PublishAt,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.ContentWarning,
+			&i.Sensitive,
+			&i.Visibility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTagChirpsDescending = `-- name: GetTagChirpsDescending :many
+SELECT chirps.id, chirps.created_at, chirps.updated_at, chirps.body, chirps.user_id, chirps.parent_id, chirps.search_vector, chirps.deleted_at, chirps.publish_at, chirps.rechirp_of, chirps.quote_of, chirps.content_warning, chirps.sensitive, chirps.visibility FROM chirps
+JOIN chirp_tags ON chirp_tags.chirp_id = chirps.id
+JOIN tags ON tags.id = chirp_tags.tag_id
+WHERE tags.name = $1
+AND chirps.deleted_at IS NULL AND chirps.publish_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, $2::uuid)
+AND ($3::timestamp IS NULL OR (chirps.created_at, chirps.id) < ($3::timestamp, $4::uuid))
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT $5
+`
+
+type GetTagChirpsDescendingParams struct {
+	Tag             string
+	ViewerID        uuid.UUID
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	RowLimit        int32
+}
+
+func (q *Queries) GetTagChirpsDescending(ctx context.Context, arg GetTagChirpsDescendingParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getTagChirpsDescending, arg.Tag, arg.ViewerID, arg.CursorCreatedAt, arg.CursorID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ParentID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.PublishAt,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.ContentWarning,
+			&i.Sensitive,
+			&i.Visibility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTimelineAscending = `-- name: GetTimelineAscending :many
+SELECT chirps.id, chirps.created_at, chirps.updated_at, chirps.body, chirps.user_id, chirps.parent_id, chirps.search_vector, chirps.deleted_at, chirps.publish_at, chirps.rechirp_of, chirps.quote_of, chirps.content_warning, chirps.sensitive, chirps.visibility FROM chirps
+JOIN follows ON follows.followed_id = chirps.user_id
+WHERE follows.follower_id = $1
+AND chirps.deleted_at IS NULL AND chirps.publish_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, $1)
+AND (chirps.rechirp_of IS NULL OR EXISTS (SELECT 1 FROM chirps original WHERE original.id = chirps.rechirp_of AND original.deleted_at IS NULL))
+AND ($2::timestamp IS NULL OR (chirps.created_at, chirps.id) > ($2::timestamp, $3::uuid))
+ORDER BY chirps.created_at ASC, chirps.id ASC
+LIMIT $4
+`
+
+type GetTimelineAscendingParams struct {
+	UserID          uuid.UUID
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	RowLimit        int32
+}
+
+func (q *Queries) GetTimelineAscending(ctx context.Context, arg GetTimelineAscendingParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getTimelineAscending, arg.UserID, arg.CursorCreatedAt, arg.CursorID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ParentID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.PublishAt,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.ContentWarning,
+			&i.Sensitive,
+			&i.Visibility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTimelineDescending = `-- name: GetTimelineDescending :many
+SELECT chirps.id, chirps.created_at, chirps.updated_at, chirps.body, chirps.user_id, chirps.parent_id, chirps.search_vector, chirps.deleted_at, chirps.publish_at, chirps.rechirp_of, chirps.quote_of, chirps.content_warning, chirps.sensitive, chirps.visibility FROM chirps
+JOIN follows ON follows.followed_id = chirps.user_id
+WHERE follows.follower_id = $1
+AND chirps.deleted_at IS NULL AND chirps.publish_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, $1)
+AND (chirps.rechirp_of IS NULL OR EXISTS (SELECT 1 FROM chirps original WHERE original.id = chirps.rechirp_of AND original.deleted_at IS NULL))
+AND ($2::timestamp IS NULL OR (chirps.created_at, chirps.id) < ($2::timestamp, $3::uuid))
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT $4
+`
+
+type GetTimelineDescendingParams struct {
+	UserID          uuid.UUID
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	RowLimit        int32
+}
+
+func (q *Queries) GetTimelineDescending(ctx context.Context, arg GetTimelineDescendingParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getTimelineDescending, arg.UserID, arg.CursorCreatedAt, arg.CursorID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ParentID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.PublishAt,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.ContentWarning,
+			&i.Sensitive,
+			&i.Visibility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrashAscending = `-- name: GetTrashAscending :many
+SELECT id, created_at, updated_at, body, user_id, parent_id, search_vector, deleted_at, publish_at, rechirp_of, quote_of, content_warning, sensitive, visibility FROM chirps
+WHERE user_id = $1
+AND deleted_at > NOW() - make_interval(secs => $2::float8)
+AND ($3::timestamp IS NULL OR (deleted_at, id) > ($3::timestamp, $4::uuid))
+ORDER BY deleted_at ASC, id ASC
+LIMIT $5
+`
+
+type GetTrashAscendingParams struct {
+	UserID           uuid.UUID
+	RetentionSeconds float64
+	CursorDeletedAt  sql.NullTime
+	CursorID         uuid.NullUUID
+	RowLimit         int32
+}
+
+func (q *Queries) GetTrashAscending(ctx context.Context, arg GetTrashAscendingParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getTrashAscending, arg.UserID, arg.RetentionSeconds, arg.CursorDeletedAt, arg.CursorID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ParentID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.PublishAt,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.ContentWarning,
+			&i.Sensitive,
+			&i.Visibility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrashDescending = `-- name: GetTrashDescending :many
+SELECT id, created_at, updated_at, body, user_id, parent_id, search_vector, deleted_at, publish_at, rechirp_of, quote_of, content_warning, sensitive, visibility FROM chirps
+WHERE user_id = $1
+AND deleted_at > NOW() - make_interval(secs => $2::float8)
+AND ($3::timestamp IS NULL OR (deleted_at, id) < ($3::timestamp, $4::uuid))
+ORDER BY deleted_at DESC, id DESC
+LIMIT $5
+`
+
+type GetTrashDescendingParams struct {
+	UserID           uuid.UUID
+	RetentionSeconds float64
+	CursorDeletedAt  sql.NullTime
+	CursorID         uuid.NullUUID
+	RowLimit         int32
+}
+
+func (q *Queries) GetTrashDescending(ctx context.Context, arg GetTrashDescendingParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getTrashDescending, arg.UserID, arg.RetentionSeconds, arg.CursorDeletedAt, arg.CursorID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ParentID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.PublishAt,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.ContentWarning,
+			&i.Sensitive,
+			&i.Visibility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const publishDueChirps = `-- name: PublishDueChirps :many
+-- chirps of authors whose email is unverified stay scheduled until they verify;
+-- publish_at is stored in UTC
+UPDATE chirps SET publish_at = NULL, created_at = NOW(), updated_at = NOW()
+WHERE id IN (
+  SELECT id FROM chirps
+  WHERE publish_at <= (NOW() AT TIME ZONE 'UTC') AND deleted_at IS NULL
+  AND EXISTS (SELECT 1 FROM users WHERE users.id = chirps.user_id AND users.email_verified)
+  ORDER BY publish_at ASC
+  LIMIT $1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, created_at, updated_at, body, user_id, parent_id, search_vector, deleted_at, publish_at, rechirp_of, quote_of, content_warning, sensitive, visibility
+`
+
+func (q *Queries) PublishDueChirps(ctx context.Context, rowLimit int32) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, publishDueChirps, rowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ParentID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.PublishAt,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.ContentWarning,
+			&i.Sensitive,
+			&i.Visibility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeDeletedChirps = `-- name: PurgeDeletedChirps :execrows
+DELETE FROM chirps WHERE deleted_at < NOW() - make_interval(secs => $1::float8)
+`
+
+func (q *Queries) PurgeDeletedChirps(ctx context.Context, retentionSeconds float64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeDeletedChirps, retentionSeconds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreChirp = `-- name: RestoreChirp :one
+UPDATE chirps SET deleted_at = NULL
+WHERE id = $1
+AND deleted_at > NOW() - make_interval(secs => $2::float8)
+RETURNING id, created_at, updated_at, body, user_id, parent_id, search_vector, deleted_at, publish_at, rechirp_of, quote_of, content_warning, sensitive, visibility
+`
+
+type RestoreChirpParams struct {
+	ID               uuid.UUID
+	RetentionSeconds float64
+}
+
+func (q *Queries) RestoreChirp(ctx context.Context, arg RestoreChirpParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, restoreChirp, arg.ID, arg.RetentionSeconds)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.ParentID,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.PublishAt,
+		&i.RechirpOf,
+		&i.QuoteOf,
+		&i.ContentWarning,
+		&i.Sensitive,
+		&i.Visibility,
+	)
+	return i, err
+}
+
+const searchChirps = `-- name: SearchChirps :many
+SELECT chirps.id, chirps.created_at, chirps.updated_at, chirps.body, chirps.user_id, chirps.parent_id, chirps.search_vector, chirps.deleted_at, chirps.publish_at, chirps.rechirp_of, chirps.quote_of, chirps.content_warning, chirps.sensitive, chirps.visibility,
+  ts_rank(chirps.search_vector, query) AS rank,
+  ts_headline('english', chirps.body, query, 'StartSel=' || chr(57344) || ', StopSel=' || chr(57345) || ', HighlightAll=true') AS highlight
+FROM chirps, websearch_to_tsquery('english', $1::text) query
+WHERE chirps.search_vector @@ query
+AND chirps.deleted_at IS NULL AND chirps.publish_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, $2::uuid)
+AND ($3::uuid = CAST('00000000-0000-0000-0000-000000000000' as uuid) OR chirps.user_id = $3::uuid)
+AND ($4::real IS NULL OR (ts_rank(chirps.search_vector, query), chirps.created_at, chirps.id) < ($4::real, $5::timestamp, $6::uuid))
+ORDER BY rank DESC, chirps.created_at DESC, chirps.id DESC
+LIMIT $7
+`
+
+type SearchChirpsParams struct {
+	Query           string
+	ViewerID        uuid.UUID
+	AuthorID        uuid.UUID
+	CursorRank      sql.NullFloat64
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	RowLimit        int32
+}
+
+type SearchChirpsRow struct {
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Body           string
+	UserID         uuid.UUID
+	ParentID       uuid.NullUUID
+	SearchVector   interface{}
+	DeletedAt      sql.NullTime
+	PublishAt      sql.NullTime
+	RechirpOf      uuid.NullUUID
+	QuoteOf        uuid.NullUUID
+	ContentWarning sql.NullString
+	Sensitive      bool
+	Visibility     string
+	Rank           float32
+	Highlight      string
+}
+
+func (q *Queries) SearchChirps(ctx context.Context, arg SearchChirpsParams) ([]SearchChirpsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchChirps, arg.Query, arg.ViewerID, arg.AuthorID, arg.CursorRank, arg.CursorCreatedAt, arg.CursorID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchChirpsRow
+	for rows.Next() {
+		var i SearchChirpsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ParentID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.PublishAt,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.ContentWarning,
+			&i.Sensitive,
+			&i.Visibility,
+			&i.Rank,
+			&i.Highlight,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setChirpContentWarning = `-- name: SetChirpContentWarning :one
+UPDATE chirps SET content_warning = $1, sensitive = $2
+WHERE id = $3 AND deleted_at IS NULL
+RETURNING id, created_at, updated_at, body, user_id, parent_id, search_vector, deleted_at, publish_at, rechirp_of, quote_of, content_warning, sensitive, visibility
+`
+
+type SetChirpContentWarningParams struct {
+	ContentWarning sql.NullString
+	Sensitive      bool
+	ID             uuid.UUID
+}
+
+func (q *Queries) SetChirpContentWarning(ctx context.Context, arg SetChirpContentWarningParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, setChirpContentWarning, arg.ContentWarning, arg.Sensitive, arg.ID)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.ParentID,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.PublishAt,
+		&i.RechirpOf,
+		&i.QuoteOf,
+		&i.ContentWarning,
+		&i.Sensitive,
+		&i.Visibility,
+	)
+	return i, err
+}
+
+const updateChirpBody = `-- name: UpdateChirpBody :one
+UPDATE chirps SET body = $1, updated_at = NOW()
+WHERE id = $2
+AND (publish_at IS NOT NULL OR $3::float8 IS NULL OR created_at > NOW() - make_interval(secs => $3::float8))
+RETURNING id, created_at, updated_at, body, user_id, parent_id, search_vector, deleted_at, publish_at, rechirp_of, quote_of, content_warning, sensitive, visibility
+`
+
+type UpdateChirpBodyParams struct {
+	Body              string
+	ID                uuid.UUID
+	EditWindowSeconds sql.NullFloat64
+}
+
+func (q *Queries) UpdateChirpBody(ctx context.Context, arg UpdateChirpBodyParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, updateChirpBody, arg.Body, arg.ID, arg.EditWindowSeconds)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.ParentID,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.PublishAt,
+		&i.RechirpOf,
+		&i.QuoteOf,
+		&i.ContentWarning,
+		&i.Sensitive,
+		&i.Visibility,
+	)
+	return i, err
+}
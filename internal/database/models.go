@@ -11,28 +11,232 @@ import (
 	"github.com/google/uuid"
 )
 
+type Attachment struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UserID       uuid.UUID
+	ChirpID      uuid.NullUUID
+	Position     int32
+	ContentType  string
+	SizeBytes    int64
+	Width        int32
+	Height       int32
+	StorageKey   string
+	ThumbnailKey string
+}
+
+type Bookmark struct {
+	UserID    uuid.UUID
+	ChirpID   uuid.UUID
+	CreatedAt time.Time
+}
+
+type ChirpRevision struct {
+	ID         uuid.UUID
+	ChirpID    uuid.UUID
+	Body       string
+	CreatedAt  time.Time
+	ReplacedAt time.Time
+}
+
+type ChirpTag struct {
+	ChirpID   uuid.UUID
+	TagID     uuid.UUID
+	CreatedAt time.Time
+}
+
+type ChirpView struct {
+	ChirpID uuid.UUID
+	Day     time.Time
+	Views   int64
+}
+
 type Chirp struct {
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Body           string
+	UserID         uuid.UUID
+	ParentID       uuid.NullUUID
+	SearchVector   interface{}
+	DeletedAt      sql.NullTime
+	PublishAt      sql.NullTime
+	RechirpOf      uuid.NullUUID
+	QuoteOf        uuid.NullUUID
+	ContentWarning sql.NullString
+	Sensitive      bool
+	Visibility     string
+}
+
+type Draft struct {
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	UserID         uuid.UUID
+	Body           string
+	ParentID       uuid.NullUUID
+	Visibility     string
+	ContentWarning sql.NullString
+	Sensitive      bool
+}
+
+type EmailVerification struct {
 	ID        uuid.UUID
 	CreatedAt time.Time
-	UpdatedAt time.Time
-	Body      string
+	ExpiresAt time.Time
+	UsedAt    sql.NullTime
 	UserID    uuid.UUID
+	Email     string
 }
 
-type RefreshToken struct {
-	Token     string
+type FilterWord struct {
+	Word      string
+	CreatedAt time.Time
+}
+
+type Follow struct {
+	FollowerID uuid.UUID
+	FollowedID uuid.UUID
+	CreatedAt  time.Time
+}
+
+type Like struct {
+	UserID    uuid.UUID
+	ChirpID   uuid.UUID
+	CreatedAt time.Time
+}
+
+type ListFollow struct {
+	ListID    uuid.UUID
+	UserID    uuid.UUID
+	CreatedAt time.Time
+}
+
+type ListMember struct {
+	ListID    uuid.UUID
+	UserID    uuid.UUID
+	CreatedAt time.Time
+}
+
+type List struct {
+	ID        uuid.UUID
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	UserID    uuid.UUID
+	Name      string
+	Private   bool
+}
+
+type LoginChallenge struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    sql.NullTime
+	Attempts  int32
+	UserID    uuid.UUID
+}
+
+type Mention struct {
+	ChirpID   uuid.UUID
+	UserID    uuid.UUID
+	CreatedAt time.Time
+}
+
+type ModerationFlag struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	ChirpID    uuid.UUID
+	Words      []string
+	ResolvedAt sql.NullTime
+}
+
+type Notification struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UserID    uuid.UUID
+	ActorID   uuid.UUID
+	Kind      string
+	ChirpID   uuid.NullUUID
+	ReadAt    sql.NullTime
+}
+
+type PasswordReset struct {
+	TokenHash string
+	CreatedAt time.Time
 	ExpiresAt time.Time
-	RevokedAt sql.NullTime
+	UsedAt    sql.NullTime
+	UserID    uuid.UUID
+}
+
+type Pin struct {
+	UserID    uuid.UUID
+	ChirpID   uuid.UUID
+	CreatedAt time.Time
+}
+
+type PollOption struct {
+	ID       uuid.UUID
+	PollID   uuid.UUID
+	Position int32
+	Text     string
+}
+
+type PollVote struct {
+	PollID    uuid.UUID
+	UserID    uuid.UUID
+	OptionID  uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Poll struct {
+	ID        uuid.UUID
+	ChirpID   uuid.UUID
+	CreatedAt time.Time
+	ClosesAt  time.Time
+}
+
+type RecoveryCode struct {
+	CodeHash  string
+	CreatedAt time.Time
+	UsedAt    sql.NullTime
+	UserID    uuid.UUID
+}
+
+type RefreshToken struct {
+	Token      string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	UserID     uuid.UUID
+	ExpiresAt  time.Time
+	RevokedAt  sql.NullTime
+	ID         uuid.UUID
+	UserAgent  string
+	IpAddress  string
+	LastUsedAt time.Time
+}
+
+type Tag struct {
+	ID        uuid.UUID
+	Name      string
+	CreatedAt time.Time
 }
 
 type User struct {
-	ID             uuid.UUID
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
-	Email          string
-	HashedPassword string
-	IsChirpyRed    bool
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Email            string
+	HashedPassword   string
+	IsChirpyRed      bool
+	SensitiveContent string
+	Handle           string
+	DisplayName      string
+	Bio              string
+	Location         string
+	AvatarID         uuid.NullUUID
+	EmailVerified    bool
+	TotpSecret       sql.NullString
+	TotpEnabled      bool
+	TotpLastStep     int64
 }
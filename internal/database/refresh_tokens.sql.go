@@ -13,19 +13,21 @@ import (
 )
 
 const addRefreshToken = `-- name: AddRefreshToken :one
-INSERT INTO refresh_tokens (token, created_at, updated_at, user_id, expires_at, revoked_at)
-VALUES ($1, NOW(), NOW(), $2, $3, NULL)
-RETURNING token, created_at, updated_at, user_id, expires_at, revoked_at
+INSERT INTO refresh_tokens (token, created_at, updated_at, user_id, expires_at, revoked_at, id, user_agent, ip_address, last_used_at)
+VALUES ($1, NOW(), NOW(), $2, $3, NULL, gen_random_uuid(), $4, $5, NOW())
+RETURNING token, created_at, updated_at, user_id, expires_at, revoked_at, id, user_agent, ip_address, last_used_at
 `
 
 type AddRefreshTokenParams struct {
 	Token     string
 	UserID    uuid.UUID
 	ExpiresAt time.Time
+	UserAgent string
+	IpAddress string
 }
 
 func (q *Queries) AddRefreshToken(ctx context.Context, arg AddRefreshTokenParams) (RefreshToken, error) {
-	row := q.db.QueryRowContext(ctx, addRefreshToken, arg.Token, arg.UserID, arg.ExpiresAt)
+	row := q.db.QueryRowContext(ctx, addRefreshToken, arg.Token, arg.UserID, arg.ExpiresAt, arg.UserAgent, arg.IpAddress)
 	var i RefreshToken
 	err := row.Scan(
 		&i.Token,
@@ -34,12 +36,56 @@ func (q *Queries) AddRefreshToken(ctx context.Context, arg AddRefreshTokenParams
 		&i.UserID,
 		&i.ExpiresAt,
 		&i.RevokedAt,
+		&i.ID,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.LastUsedAt,
 	)
 	return i, err
 }
 
+const getSessions = `-- name: GetSessions :many
+SELECT token, created_at, updated_at, user_id, expires_at, revoked_at, id, user_agent, ip_address, last_used_at FROM refresh_tokens
+WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+ORDER BY last_used_at DESC
+`
+
+func (q *Queries) GetSessions(ctx context.Context, userID uuid.UUID) ([]RefreshToken, error) {
+	rows, err := q.db.QueryContext(ctx, getSessions, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RefreshToken
+	for rows.Next() {
+		var i RefreshToken
+		if err := rows.Scan(
+			&i.Token,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.ExpiresAt,
+			&i.RevokedAt,
+			&i.ID,
+			&i.UserAgent,
+			&i.IpAddress,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserFromRefreshToken = `-- name: GetUserFromRefreshToken :one
-SELECT token, created_at, updated_at, user_id, expires_at, revoked_at FROM refresh_tokens WHERE token = $1
+SELECT token, created_at, updated_at, user_id, expires_at, revoked_at, id, user_agent, ip_address, last_used_at FROM refresh_tokens WHERE token = $1
 `
 
 func (q *Queries) GetUserFromRefreshToken(ctx context.Context, token string) (RefreshToken, error) {
@@ -52,10 +98,32 @@ func (q *Queries) GetUserFromRefreshToken(ctx context.Context, token string) (Re
 		&i.UserID,
 		&i.ExpiresAt,
 		&i.RevokedAt,
+		&i.ID,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.LastUsedAt,
 	)
 	return i, err
 }
 
+const revokeSession = `-- name: RevokeSession :execrows
+UPDATE refresh_tokens SET revoked_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+`
+
+type RevokeSessionParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) RevokeSession(ctx context.Context, arg RevokeSessionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokeSession, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const revokeToken = `-- name: RevokeToken :exec
 UPDATE refresh_tokens SET revoked_at = NOW(), updated_at = NOW() where token = $1
 `
@@ -64,3 +132,27 @@ func (q *Queries) RevokeToken(ctx context.Context, token string) error {
 	_, err := q.db.ExecContext(ctx, revokeToken, token)
 	return err
 }
+
+const revokeUserTokens = `-- name: RevokeUserTokens :exec
+UPDATE refresh_tokens SET revoked_at = NOW(), updated_at = NOW()
+WHERE user_id = $1 AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeUserTokens(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, revokeUserTokens, userID)
+	return err
+}
+
+const touchRefreshToken = `-- name: TouchRefreshToken :exec
+UPDATE refresh_tokens SET last_used_at = NOW(), ip_address = $1 WHERE token = $2
+`
+
+type TouchRefreshTokenParams struct {
+	IpAddress string
+	Token     string
+}
+
+func (q *Queries) TouchRefreshToken(ctx context.Context, arg TouchRefreshTokenParams) error {
+	_, err := q.db.ExecContext(ctx, touchRefreshToken, arg.IpAddress, arg.Token)
+	return err
+}
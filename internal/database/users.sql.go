@@ -12,24 +12,26 @@ import (
 )
 
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (id, created_at, updated_at, email, hashed_password)
+INSERT INTO users (id, created_at, updated_at, email, hashed_password, handle)
 VALUES (
   gen_random_uuid(),
   NOW(),
   NOW(),
   $1,
-  $2
+  $2,
+  $3
 )
-RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red
+RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red, sensitive_content, handle, display_name, bio, location, avatar_id, email_verified, totp_secret, totp_enabled, totp_last_step
 `
 
 type CreateUserParams struct {
 	Email          string
 	HashedPassword string
+	Handle         string
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, createUser, arg.Email, arg.HashedPassword)
+	row := q.db.QueryRowContext(ctx, createUser, arg.Email, arg.HashedPassword, arg.Handle)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -38,6 +40,16 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.HashedPassword,
 		&i.IsChirpyRed,
+		&i.SensitiveContent,
+		&i.Handle,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Location,
+		&i.AvatarID,
+		&i.EmailVerified,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastStep,
 	)
 	return i, err
 }
@@ -51,8 +63,92 @@ func (q *Queries) DeleteUsers(ctx context.Context) error {
 	return err
 }
 
+const getUser = `-- name: GetUser :one
+SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red, sensitive_content, handle, display_name, bio, location, avatar_id, email_verified, totp_secret, totp_enabled, totp_last_step FROM users WHERE id = $1
+`
+
+func (q *Queries) GetUser(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Email,
+		&i.HashedPassword,
+		&i.IsChirpyRed,
+		&i.SensitiveContent,
+		&i.Handle,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Location,
+		&i.AvatarID,
+		&i.EmailVerified,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const getUserByHandle = `-- name: GetUserByHandle :one
+SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red, sensitive_content, handle, display_name, bio, location, avatar_id, email_verified, totp_secret, totp_enabled, totp_last_step FROM users WHERE handle = $1
+`
+
+func (q *Queries) GetUserByHandle(ctx context.Context, handle string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByHandle, handle)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Email,
+		&i.HashedPassword,
+		&i.IsChirpyRed,
+		&i.SensitiveContent,
+		&i.Handle,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Location,
+		&i.AvatarID,
+		&i.EmailVerified,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const getUserForUpdate = `-- name: GetUserForUpdate :one
+SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red, sensitive_content, handle, display_name, bio, location, avatar_id, email_verified, totp_secret, totp_enabled, totp_last_step FROM users WHERE id = $1 FOR UPDATE
+`
+
+func (q *Queries) GetUserForUpdate(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserForUpdate, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Email,
+		&i.HashedPassword,
+		&i.IsChirpyRed,
+		&i.SensitiveContent,
+		&i.Handle,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Location,
+		&i.AvatarID,
+		&i.EmailVerified,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
 const getUserFromEmail = `-- name: GetUserFromEmail :one
-SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red FROM users WHERE email = $1
+SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red, sensitive_content, handle, display_name, bio, location, avatar_id, email_verified, totp_secret, totp_enabled, totp_last_step FROM users WHERE email = $1
 `
 
 func (q *Queries) GetUserFromEmail(ctx context.Context, email string) (User, error) {
@@ -65,22 +161,90 @@ func (q *Queries) GetUserFromEmail(ctx context.Context, email string) (User, err
 		&i.Email,
 		&i.HashedPassword,
 		&i.IsChirpyRed,
+		&i.SensitiveContent,
+		&i.Handle,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Location,
+		&i.AvatarID,
+		&i.EmailVerified,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const updatePassword = `-- name: UpdatePassword :exec
+UPDATE users SET hashed_password = $1, updated_at = NOW() WHERE id = $2
+`
+
+type UpdatePasswordParams struct {
+	HashedPassword string
+	ID             uuid.UUID
+}
+
+func (q *Queries) UpdatePassword(ctx context.Context, arg UpdatePasswordParams) error {
+	_, err := q.db.ExecContext(ctx, updatePassword, arg.HashedPassword, arg.ID)
+	return err
+}
+
+const updateSensitiveContent = `-- name: UpdateSensitiveContent :one
+UPDATE users SET sensitive_content = $1, updated_at = NOW() WHERE id = $2 RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red, sensitive_content, handle, display_name, bio, location, avatar_id, email_verified, totp_secret, totp_enabled, totp_last_step
+`
+
+type UpdateSensitiveContentParams struct {
+	SensitiveContent string
+	ID               uuid.UUID
+}
+
+func (q *Queries) UpdateSensitiveContent(ctx context.Context, arg UpdateSensitiveContentParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateSensitiveContent, arg.SensitiveContent, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Email,
+		&i.HashedPassword,
+		&i.IsChirpyRed,
+		&i.SensitiveContent,
+		&i.Handle,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Location,
+		&i.AvatarID,
+		&i.EmailVerified,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastStep,
 	)
 	return i, err
 }
 
 const updateUser = `-- name: UpdateUser :one
-UPDATE users SET email = $1, hashed_password = $2, updated_at = NOW() WHERE id = $3 RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red
+UPDATE users SET email = $1, hashed_password = $2,
+  handle = $3, display_name = $4, bio = $5,
+  location = $6, avatar_id = $7,
+  email_verified = $8, updated_at = NOW()
+WHERE id = $9
+RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red, sensitive_content, handle, display_name, bio, location, avatar_id, email_verified, totp_secret, totp_enabled, totp_last_step
 `
 
 type UpdateUserParams struct {
 	Email          string
 	HashedPassword string
+	Handle         string
+	DisplayName    string
+	Bio            string
+	Location       string
+	AvatarID       uuid.NullUUID
+	EmailVerified  bool
 	ID             uuid.UUID
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUser, arg.Email, arg.HashedPassword, arg.ID)
+	row := q.db.QueryRowContext(ctx, updateUser, arg.Email, arg.HashedPassword, arg.Handle, arg.DisplayName, arg.Bio, arg.Location, arg.AvatarID, arg.EmailVerified, arg.ID)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -89,12 +253,22 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.Email,
 		&i.HashedPassword,
 		&i.IsChirpyRed,
+		&i.SensitiveContent,
+		&i.Handle,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Location,
+		&i.AvatarID,
+		&i.EmailVerified,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastStep,
 	)
 	return i, err
 }
 
 const upgradeUser = `-- name: UpgradeUser :one
-UPDATE users SET is_chirpy_red = TRUE where id = $1 RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red
+UPDATE users SET is_chirpy_red = TRUE where id = $1 RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red, sensitive_content, handle, display_name, bio, location, avatar_id, email_verified, totp_secret, totp_enabled, totp_last_step
 `
 
 func (q *Queries) UpgradeUser(ctx context.Context, id uuid.UUID) (User, error) {
@@ -107,6 +281,33 @@ func (q *Queries) UpgradeUser(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.Email,
 		&i.HashedPassword,
 		&i.IsChirpyRed,
+		&i.SensitiveContent,
+		&i.Handle,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Location,
+		&i.AvatarID,
+		&i.EmailVerified,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastStep,
 	)
 	return i, err
 }
+
+const verifyEmail = `-- name: VerifyEmail :execrows
+UPDATE users SET email_verified = TRUE, updated_at = NOW() WHERE id = $1 AND email = $2
+`
+
+type VerifyEmailParams struct {
+	ID    uuid.UUID
+	Email string
+}
+
+func (q *Queries) VerifyEmail(ctx context.Context, arg VerifyEmailParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, verifyEmail, arg.ID, arg.Email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,164 @@
+package filter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Mode decides what happens to a chirp that contains a filtered word.
+type Mode string
+
+const (
+	// ModeMask replaces filtered words with "****".
+	ModeMask Mode = "mask"
+	// ModeReject refuses the chirp altogether.
+	ModeReject Mode = "reject"
+	// ModeFlag keeps the chirp as written and flags it for review.
+	ModeFlag Mode = "flag"
+)
+
+const mask = "****"
+
+func ParseMode(value string) (Mode, error) {
+	switch Mode(value) {
+	case "":
+		return ModeMask, nil
+	case ModeMask, ModeReject, ModeFlag:
+		return Mode(value), nil
+	}
+	return "", fmt.Errorf("invalid filter mode: %v", value)
+}
+
+// Filter matches chirp bodies against a word list that can be changed while
+// the server is running.
+type Filter struct {
+	mode  Mode
+	mu    sync.RWMutex
+	words map[string]bool
+}
+
+type Result struct {
+	// Masked is the body with every filtered word replaced by "****".
+	Masked string
+	// Matches are the filtered words found in the body, without duplicates.
+	Matches []string
+}
+
+func New(mode Mode, words []string) *Filter {
+	f := &Filter{mode: mode, words: map[string]bool{}}
+	f.Add(words...)
+	return f
+}
+
+func (f *Filter) Mode() Mode {
+	return f.mode
+}
+
+// Words returns the word list in alphabetical order.
+func (f *Filter) Words() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	words := make([]string, 0, len(f.words))
+	for word := range f.words {
+		words = append(words, word)
+	}
+	slices.Sort(words)
+	return words
+}
+
+func (f *Filter) Add(words ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, word := range words {
+		if word = NormalizeWord(word); len(word) > 0 {
+			f.words[word] = true
+		}
+	}
+}
+
+func (f *Filter) Remove(words ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, word := range words {
+		delete(f.words, NormalizeWord(word))
+	}
+}
+
+// Check looks for filtered words in body. Words are compared case-insensitively
+// and are split on anything that is not a letter or digit, so punctuation
+// around a word ("Kerfuffle!") does not hide it.
+func (f *Filter) Check(body string) Result {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	result := Result{}
+	var masked strings.Builder
+	runes := []rune(body)
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			masked.WriteRune(runes[i])
+			i++
+			continue
+		}
+		end := i
+		for end < len(runes) && isWordRune(runes[end]) {
+			end++
+		}
+		word := string(runes[i:end])
+		normalized := strings.ToLower(word)
+		if f.words[normalized] {
+			masked.WriteString(mask)
+			if !slices.Contains(result.Matches, normalized) {
+				result.Matches = append(result.Matches, normalized)
+			}
+		} else {
+			masked.WriteString(word)
+		}
+		i = end
+	}
+	result.Masked = masked.String()
+	return result
+}
+
+// NormalizeWord lowercases and trims word. It returns "" when word is not a
+// single run of letters and digits, since Check could never match it.
+func NormalizeWord(word string) string {
+	word = strings.ToLower(strings.TrimSpace(word))
+	for _, r := range word {
+		if !isWordRune(r) {
+			return ""
+		}
+	}
+	return word
+}
+
+// ReadWords reads a word list with one word per line. Blank lines and lines
+// starting with '#' are skipped.
+func ReadWords(r io.Reader) ([]string, error) {
+	var words []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		word := NormalizeWord(line)
+		if len(word) == 0 {
+			return nil, fmt.Errorf("invalid filter word: %v", line)
+		}
+		words = append(words, word)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,67 @@
+package filter
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	f := New(ModeMask, []string{"kerfuffle", "Sharbert", "fornax"})
+
+	cases := []struct {
+		body    string
+		masked  string
+		matches []string
+	}{
+		{"This is a kerfuffle opinion I need to share with the world", "This is a **** opinion I need to share with the world", []string{"kerfuffle"}},
+		{"I really need a Kerfuffle! to go to bed sooner, Fornax.", "I really need a ****! to go to bed sooner, ****.", []string{"kerfuffle", "fornax"}},
+		{"sharbert,sharbert and (SHARBERT)", "****,**** and (****)", []string{"sharbert"}},
+		{"kerfuffles are fine", "kerfuffles are fine", nil},
+	}
+	for _, c := range cases {
+		result := f.Check(c.body)
+		if result.Masked != c.masked {
+			t.Fatalf("Check(%q) masked to %q, expected %q", c.body, result.Masked, c.masked)
+		}
+		if !slices.Equal(result.Matches, c.matches) {
+			t.Fatalf("Check(%q) matched %v, expected %v", c.body, result.Matches, c.matches)
+		}
+	}
+}
+
+func TestAddRemove(t *testing.T) {
+	f := New(ModeReject, nil)
+	f.Add("Fornax", "bad word")
+	if words := f.Words(); !slices.Equal(words, []string{"fornax"}) {
+		t.Fatalf("Unexpected words %v", words)
+	}
+
+	f.Remove("FORNAX")
+	if matches := f.Check("fornax").Matches; len(matches) != 0 {
+		t.Fatalf("Removed word still matched: %v", matches)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	if mode, err := ParseMode(""); err != nil || mode != ModeMask {
+		t.Fatalf("Expected default mode mask, got %v %v", mode, err)
+	}
+	if _, err := ParseMode("shout"); err == nil {
+		t.Fatalf("Expected error for unknown mode")
+	}
+}
+
+func TestReadWords(t *testing.T) {
+	words, err := ReadWords(strings.NewReader("# default list\nKerfuffle\n\n  sharbert  \n"))
+	if err != nil {
+		t.Fatalf("Failed to read words: %v", err)
+	}
+	if !slices.Equal(words, []string{"kerfuffle", "sharbert"}) {
+		t.Fatalf("Unexpected words %v", words)
+	}
+
+	if _, err := ReadWords(strings.NewReader("two words\n")); err == nil {
+		t.Fatalf("Expected error for invalid word")
+	}
+}
@@ -0,0 +1,134 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends email on behalf of the server.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// format renders msg as an RFC 5322 message.
+func format(from string, msg Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// checkHeaders guards against header injection through the recipient or
+// subject.
+func checkHeaders(msg Message) error {
+	if strings.ContainsAny(msg.To, "\r\n") || strings.ContainsAny(msg.Subject, "\r\n") {
+		return fmt.Errorf("invalid message headers")
+	}
+	return nil
+}
+
+// SMTP sends email through an SMTP server, authenticating with PLAIN auth
+// when a username is set.
+type SMTP struct {
+	addr     string
+	from     string
+	username string
+	password string
+}
+
+func NewSMTP(host, port, username, password, from string) *SMTP {
+	return &SMTP{
+		addr:     net.JoinHostPort(host, port),
+		from:     from,
+		username: username,
+		password: password,
+	}
+}
+
+func (s *SMTP) Send(ctx context.Context, msg Message) error {
+	if err := checkHeaders(msg); err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if len(s.username) > 0 {
+		host, _, _ := net.SplitHostPort(s.addr)
+		auth = smtp.PlainAuth("", s.username, s.password, host)
+	}
+	return smtp.SendMail(s.addr, auth, s.from, []string{msg.To}, format(s.from, msg))
+}
+
+// Dir writes each email to a file in a directory instead of sending it, for
+// local development.
+type Dir struct {
+	dir  string
+	from string
+}
+
+func NewDir(dir, from string) (*Dir, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("error creating mail directory: %v", err)
+	}
+	return &Dir{dir: dir, from: from}, nil
+}
+
+func (d *Dir) Send(ctx context.Context, msg Message) error {
+	if err := checkHeaders(msg); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(d.dir, time.Now().UTC().Format("20060102T150405")+"-*.eml")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(format(d.from, msg)); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Memory keeps sent emails in memory so that tests can inspect them.
+type Memory struct {
+	mu   sync.Mutex
+	sent []Message
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (m *Memory) Send(ctx context.Context, msg Message) error {
+	if err := checkHeaders(msg); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// Sent returns the emails sent so far, oldest first.
+func (m *Memory) Sent() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.sent...)
+}
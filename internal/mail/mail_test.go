@@ -0,0 +1,52 @@
+package mail
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMemory(t *testing.T) {
+	m := NewMemory()
+	msg := Message{To: "jane@example.com", Subject: "Hello", Body: "Hi Jane"}
+	if err := m.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	sent := m.Sent()
+	if len(sent) != 1 || sent[0] != msg {
+		t.Fatalf("Sent() = %v, expected [%v]", sent, msg)
+	}
+}
+
+func TestDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	d, err := NewDir(dir, "chirpy@example.com")
+	if err != nil {
+		t.Fatalf("NewDir failed: %v", err)
+	}
+	err = d.Send(context.Background(), Message{To: "jane@example.com", Subject: "Hello", Body: "line one\nline two"})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("Expected one email file, found %v", files)
+	}
+	data, _ := os.ReadFile(files[0])
+	for _, want := range []string{"From: chirpy@example.com\r\n", "To: jane@example.com\r\n", "Subject: Hello\r\n", "\r\n\r\nline one\r\nline two"} {
+		if !strings.Contains(string(data), want) {
+			t.Fatalf("Email %q does not contain %q", data, want)
+		}
+	}
+}
+
+func TestHeaderInjection(t *testing.T) {
+	m := NewMemory()
+	err := m.Send(context.Background(), Message{To: "jane@example.com\r\nBcc: everyone@example.com", Subject: "Hello"})
+	if err == nil {
+		t.Fatalf("Expected recipient with a line break to be rejected")
+	}
+}
@@ -0,0 +1,206 @@
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	// MaxBytes is the largest upload accepted for a single image.
+	MaxBytes = 5 << 20
+	// MaxDimension is the largest width or height accepted for an image.
+	MaxDimension = 8192
+	// ThumbnailSize is the longest side of generated thumbnails.
+	ThumbnailSize = 320
+
+	jpegQuality = 85
+)
+
+// Image is an upload that passed validation and was re-encoded. Re-encoding
+// drops EXIF and any other metadata the client sent along.
+type Image struct {
+	ContentType string
+	Ext         string
+	Data        []byte
+	Width       int
+	Height      int
+
+	ThumbnailContentType string
+	ThumbnailExt         string
+	Thumbnail            []byte
+}
+
+// Process validates an uploaded image, strips its metadata and generates a
+// thumbnail. JPEG, PNG and GIF images are supported.
+func Process(data []byte) (Image, error) {
+	if len(data) > MaxBytes {
+		return Image{}, fmt.Errorf("image is larger than %d bytes", MaxBytes)
+	}
+
+	contentType := http.DetectContentType(data)
+	if contentType != "image/jpeg" && contentType != "image/png" && contentType != "image/gif" {
+		return Image{}, fmt.Errorf("unsupported content type: %v", contentType)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, fmt.Errorf("invalid image: %v", err)
+	}
+	if config.Width > MaxDimension || config.Height > MaxDimension {
+		return Image{}, fmt.Errorf("image is larger than %dx%d", MaxDimension, MaxDimension)
+	}
+
+	switch contentType {
+	case "image/jpeg":
+		return processJPEG(data)
+	case "image/png":
+		return processPNG(data)
+	}
+	return processGIF(data)
+}
+
+func processJPEG(data []byte) (Image, error) {
+	decoded, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, fmt.Errorf("invalid image: %v", err)
+	}
+	// the orientation lives in the EXIF data we are about to drop, so bake it
+	// into the pixels first
+	img := orient(toRGBA(decoded), exifOrientation(data))
+
+	var out bytes.Buffer
+	err = jpeg.Encode(&out, img, &jpeg.Options{Quality: jpegQuality})
+	if err != nil {
+		return Image{}, err
+	}
+	var thumb bytes.Buffer
+	err = jpeg.Encode(&thumb, thumbnail(img, ThumbnailSize), &jpeg.Options{Quality: jpegQuality})
+	if err != nil {
+		return Image{}, err
+	}
+
+	return Image{
+		ContentType:          "image/jpeg",
+		Ext:                  ".jpg",
+		Data:                 out.Bytes(),
+		Width:                img.Bounds().Dx(),
+		Height:               img.Bounds().Dy(),
+		ThumbnailContentType: "image/jpeg",
+		ThumbnailExt:         ".jpg",
+		Thumbnail:            thumb.Bytes(),
+	}, nil
+}
+
+func processPNG(data []byte) (Image, error) {
+	decoded, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, fmt.Errorf("invalid image: %v", err)
+	}
+
+	var out bytes.Buffer
+	err = png.Encode(&out, decoded)
+	if err != nil {
+		return Image{}, err
+	}
+	var thumb bytes.Buffer
+	err = png.Encode(&thumb, thumbnail(toRGBA(decoded), ThumbnailSize))
+	if err != nil {
+		return Image{}, err
+	}
+
+	return Image{
+		ContentType:          "image/png",
+		Ext:                  ".png",
+		Data:                 out.Bytes(),
+		Width:                decoded.Bounds().Dx(),
+		Height:               decoded.Bounds().Dy(),
+		ThumbnailContentType: "image/png",
+		ThumbnailExt:         ".png",
+		Thumbnail:            thumb.Bytes(),
+	}, nil
+}
+
+func processGIF(data []byte) (Image, error) {
+	decoded, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, fmt.Errorf("invalid image: %v", err)
+	}
+
+	// EncodeAll keeps the frames and loop count but not comment or
+	// application extensions
+	var out bytes.Buffer
+	err = gif.EncodeAll(&out, decoded)
+	if err != nil {
+		return Image{}, err
+	}
+	var thumb bytes.Buffer
+	err = png.Encode(&thumb, thumbnail(toRGBA(decoded.Image[0]), ThumbnailSize))
+	if err != nil {
+		return Image{}, err
+	}
+
+	return Image{
+		ContentType:          "image/gif",
+		Ext:                  ".gif",
+		Data:                 out.Bytes(),
+		Width:                decoded.Config.Width,
+		Height:               decoded.Config.Height,
+		ThumbnailContentType: "image/png",
+		ThumbnailExt:         ".png",
+		Thumbnail:            thumb.Bytes(),
+	}, nil
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba
+}
+
+// thumbnail scales src down so its longest side is at most size, averaging
+// the source pixels that fall into each thumbnail pixel.
+func thumbnail(src *image.RGBA, size int) *image.RGBA {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	if w <= size && h <= size {
+		return src
+	}
+	tw, th := size, size
+	if w > h {
+		th = max(1, h*size/w)
+	} else {
+		tw = max(1, w*size/h)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for ty := 0; ty < th; ty++ {
+		y0, y1 := ty*h/th, (ty+1)*h/th
+		for tx := 0; tx < tw; tx++ {
+			x0, x1 := tx*w/tw, (tx+1)*w/tw
+			var sum [4]int
+			for y := y0; y < y1; y++ {
+				row := src.Pix[y*src.Stride:]
+				for x := x0; x < x1; x++ {
+					for c := 0; c < 4; c++ {
+						sum[c] += int(row[x*4+c])
+					}
+				}
+			}
+			count := (y1 - y0) * (x1 - x0)
+			offset := ty*dst.Stride + tx*4
+			for c := 0; c < 4; c++ {
+				dst.Pix[offset+c] = uint8(sum[c] / count)
+			}
+		}
+	}
+	return dst
+}
@@ -0,0 +1,112 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func testImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+	return img
+}
+
+// withOrientation inserts an APP1 EXIF segment carrying orientation right
+// after the SOI marker of a JPEG.
+func withOrientation(jpegData []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	ifd := make([]byte, 2+12+4)
+	binary.BigEndian.PutUint16(ifd[0:], 1)
+	binary.BigEndian.PutUint16(ifd[2:], 0x0112)
+	binary.BigEndian.PutUint16(ifd[4:], 3)
+	binary.BigEndian.PutUint32(ifd[6:], 1)
+	binary.BigEndian.PutUint16(ifd[10:], orientation)
+	segment := append([]byte("Exif\x00\x00"), append(tiff, ifd...)...)
+
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	out := append([]byte{}, jpegData[:2]...)
+	out = append(out, app1...)
+	return append(out, jpegData[2:]...)
+}
+
+func TestProcessJPEGStripsEXIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testImage(40, 20), nil); err != nil {
+		t.Fatalf("Failed to encode test image: %v", err)
+	}
+	data := withOrientation(buf.Bytes(), 6)
+	if exifOrientation(data) != 6 {
+		t.Fatalf("Test image does not carry orientation 6")
+	}
+
+	processed, err := Process(data)
+	if err != nil {
+		t.Fatalf("Failed to process image: %v", err)
+	}
+	if bytes.Contains(processed.Data, []byte("Exif")) {
+		t.Fatalf("EXIF data was not stripped")
+	}
+	if processed.ContentType != "image/jpeg" || processed.Width != 20 || processed.Height != 40 {
+		t.Fatalf("Unexpected result %v %dx%d", processed.ContentType, processed.Width, processed.Height)
+	}
+}
+
+func TestProcessPNGThumbnail(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage(1000, 500)); err != nil {
+		t.Fatalf("Failed to encode test image: %v", err)
+	}
+
+	processed, err := Process(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to process image: %v", err)
+	}
+
+	thumb, err := png.Decode(bytes.NewReader(processed.Thumbnail))
+	if err != nil {
+		t.Fatalf("Failed to decode thumbnail: %v", err)
+	}
+	if thumb.Bounds().Dx() != ThumbnailSize || thumb.Bounds().Dy() != ThumbnailSize/2 {
+		t.Fatalf("Unexpected thumbnail size %v", thumb.Bounds())
+	}
+}
+
+func TestProcessRejectsNonImages(t *testing.T) {
+	if _, err := Process([]byte("definitely not an image")); err == nil {
+		t.Fatalf("Expected error for text upload")
+	}
+}
+
+func TestOrient(t *testing.T) {
+	src := testImage(3, 2)
+	cases := map[int][2]int{
+		2: {3, 2},
+		3: {3, 2},
+		6: {2, 3},
+		8: {2, 3},
+	}
+	for orientation, size := range cases {
+		dst := orient(src, orientation)
+		if dst.Bounds().Dx() != size[0] || dst.Bounds().Dy() != size[1] {
+			t.Fatalf("orient(%d) produced %v", orientation, dst.Bounds())
+		}
+	}
+
+	// rotating 90 degrees clockwise moves the bottom left pixel to the top left
+	rotated := orient(src, 6)
+	if rotated.RGBAAt(0, 0) != src.RGBAAt(0, 1) {
+		t.Fatalf("orient(6) top left is %v, expected %v", rotated.RGBAAt(0, 0), src.RGBAAt(0, 1))
+	}
+}
@@ -0,0 +1,96 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Cursor marks a position in a listing ordered by (created_at, id). Clients
+// only ever see the encoded form and should treat it as opaque.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+func (c Cursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(encoded string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid cursor")
+	}
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return Cursor{}, fmt.Errorf("invalid cursor")
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid cursor")
+	}
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid cursor")
+	}
+	return Cursor{CreatedAt: createdAt, ID: id}, nil
+}
+
+// RankedCursor marks a position in a listing ordered by a relevance score
+// first and (created_at, id) second, such as search results.
+type RankedCursor struct {
+	Rank float32
+	Cursor
+}
+
+func (c RankedCursor) Encode() string {
+	raw := strconv.FormatFloat(float64(c.Rank), 'g', -1, 32) + "|" + c.Cursor.Encode()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeRankedCursor(encoded string) (RankedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return RankedCursor{}, fmt.Errorf("invalid cursor")
+	}
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return RankedCursor{}, fmt.Errorf("invalid cursor")
+	}
+	rank, err := strconv.ParseFloat(parts[0], 32)
+	if err != nil {
+		return RankedCursor{}, fmt.Errorf("invalid cursor")
+	}
+	cursor, err := DecodeCursor(parts[1])
+	if err != nil {
+		return RankedCursor{}, err
+	}
+	return RankedCursor{Rank: float32(rank), Cursor: cursor}, nil
+}
+
+// ParseLimit reads the "limit" query value, falling back to DefaultLimit when
+// it is empty and capping it at MaxLimit.
+func ParseLimit(value string) (int, error) {
+	if len(value) == 0 {
+		return DefaultLimit, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 {
+		return 0, fmt.Errorf("invalid limit")
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+	return limit, nil
+}
@@ -0,0 +1,76 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := Cursor{
+		CreatedAt: time.Date(2025, 2, 14, 9, 30, 0, 123456000, time.UTC),
+		ID:        uuid.New(),
+	}
+
+	decoded, err := DecodeCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("Failed to decode cursor: %v", err)
+	}
+	if !decoded.CreatedAt.Equal(cursor.CreatedAt) || decoded.ID != cursor.ID {
+		t.Fatalf("Decoded cursor %v does not match %v", decoded, cursor)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	for _, encoded := range []string{"", "not-a-cursor", "bm8tc2VwYXJhdG9y"} {
+		if _, err := DecodeCursor(encoded); err == nil {
+			t.Fatalf("Expected error for cursor %q", encoded)
+		}
+	}
+}
+
+func TestRankedCursorRoundTrip(t *testing.T) {
+	cursor := RankedCursor{
+		Rank: 0.0607927,
+		Cursor: Cursor{
+			CreatedAt: time.Date(2025, 2, 14, 9, 30, 0, 0, time.UTC),
+			ID:        uuid.New(),
+		},
+	}
+
+	decoded, err := DecodeRankedCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("Failed to decode ranked cursor: %v", err)
+	}
+	if decoded.Rank != cursor.Rank || !decoded.CreatedAt.Equal(cursor.CreatedAt) || decoded.ID != cursor.ID {
+		t.Fatalf("Decoded cursor %v does not match %v", decoded, cursor)
+	}
+
+	if _, err := DecodeRankedCursor(cursor.Cursor.Encode()); err == nil {
+		t.Fatalf("Expected error decoding a plain cursor as a ranked cursor")
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	cases := map[string]int{
+		"":     DefaultLimit,
+		"5":    5,
+		"1000": MaxLimit,
+	}
+	for input, expected := range cases {
+		limit, err := ParseLimit(input)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %v", input, err)
+		}
+		if limit != expected {
+			t.Fatalf("ParseLimit(%q) = %d, expected %d", input, limit, expected)
+		}
+	}
+
+	for _, input := range []string{"0", "-3", "ten"} {
+		if _, err := ParseLimit(input); err == nil {
+			t.Fatalf("Expected error for limit %q", input)
+		}
+	}
+}
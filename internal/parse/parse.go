@@ -0,0 +1,68 @@
+package parse
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Hashtags returns the normalized, de-duplicated tags in body in the order
+// they first appear. A tag is a '#' that does not follow a word character,
+// followed by letters, digits or underscores with at least one letter.
+func Hashtags(body string) []string {
+	return entities(body, '#', isWordRune, NormalizeTag)
+}
+
+// Mentions returns the lowercased, de-duplicated handles mentioned in body
+// with '@' in the order they first appear. Handles are made of letters,
+// digits and underscores, so "@jane_doe." mentions jane_doe.
+func Mentions(body string) []string {
+	return entities(body, '@', isWordRune, strings.ToLower)
+}
+
+// NormalizeTag lowercases tag and strips a leading '#'. It returns "" when
+// what is left is not a valid tag.
+func NormalizeTag(tag string) string {
+	tag = strings.ToLower(strings.TrimPrefix(tag, "#"))
+	hasLetter := false
+	for _, r := range tag {
+		if !isWordRune(r) {
+			return ""
+		}
+		if unicode.IsLetter(r) {
+			hasLetter = true
+		}
+	}
+	if !hasLetter {
+		return ""
+	}
+	return tag
+}
+
+// entities scans body for runs of runes accepted by accept that are introduced
+// by marker, and returns the unique non-empty results of normalize applied to
+// each of them.
+func entities(body string, marker rune, accept func(rune) bool, normalize func(string) string) []string {
+	var found []string
+	seen := map[string]bool{}
+	runes := []rune(body)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != marker || (i > 0 && isWordRune(runes[i-1])) {
+			continue
+		}
+		end := i + 1
+		for end < len(runes) && accept(runes[end]) {
+			end++
+		}
+		entity := normalize(string(runes[i+1 : end]))
+		if len(entity) > 0 && !seen[entity] {
+			seen[entity] = true
+			found = append(found, entity)
+		}
+		i = end - 1
+	}
+	return found
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,49 @@
+package parse
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestHashtags(t *testing.T) {
+	cases := map[string][]string{
+		"no tags here":                     nil,
+		"#Go is fun":                       {"go"},
+		"loving #golang, #Golang and #Go!": {"golang", "go"},
+		"issue#42 and #42 are not tags":    nil,
+		"#snake_case #café":                {"snake_case", "café"},
+		"(#first)#second":                  {"first", "second"},
+		"trailing hash # and ## double":    nil,
+		"mixed #a1 #1a":                    {"a1", "1a"},
+	}
+	for body, expected := range cases {
+		tags := Hashtags(body)
+		if !slices.Equal(tags, expected) {
+			t.Fatalf("Hashtags(%q) = %v, expected %v", body, tags, expected)
+		}
+	}
+}
+
+func TestMentions(t *testing.T) {
+	cases := map[string][]string{
+		"hello @Jane":                         {"jane"},
+		"cc @jane_doe, @bob-smith and @jane.": {"jane_doe", "bob", "jane"},
+		"mail me at jane@example.com":         nil,
+		"@a+tag @ alone":                      {"a"},
+	}
+	for body, expected := range cases {
+		mentions := Mentions(body)
+		if !slices.Equal(mentions, expected) {
+			t.Fatalf("Mentions(%q) = %v, expected %v", body, mentions, expected)
+		}
+	}
+}
+
+func TestNormalizeTag(t *testing.T) {
+	if tag := NormalizeTag("#ChirpyRed"); tag != "chirpyred" {
+		t.Fatalf("NormalizeTag returned %q", tag)
+	}
+	if tag := NormalizeTag("bad-tag"); tag != "" {
+		t.Fatalf("Expected invalid tag, got %q", tag)
+	}
+}
@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+// Storage keeps uploaded files. Keys are flat file names such as
+// "3f6c...e1.jpg"; they never contain path separators.
+type Storage interface {
+	Save(ctx context.Context, key string, data []byte) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Local stores files in a directory on the local disk.
+type Local struct {
+	dir string
+}
+
+func NewLocal(dir string) (*Local, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("error creating storage directory: %v", err)
+	}
+	return &Local{dir: dir}, nil
+}
+
+func (l *Local) Save(ctx context.Context, key string, data []byte) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	// write to a temporary file first so readers never see a partial file
+	tmp, err := os.CreateTemp(l.dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (l *Local) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (l *Local) path(key string) (string, error) {
+	if len(key) == 0 || key[0] == '.' || filepath.Base(key) != key {
+		return "", fmt.Errorf("invalid storage key: %q", key)
+	}
+	return filepath.Join(l.dir, key), nil
+}
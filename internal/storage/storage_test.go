@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestLocalRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	local, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	err = local.Save(ctx, "chirp.png", []byte("image data"))
+	if err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+
+	file, err := local.Open(ctx, "chirp.png")
+	if err != nil {
+		t.Fatalf("Failed to open: %v", err)
+	}
+	data, _ := io.ReadAll(file)
+	file.Close()
+	if string(data) != "image data" {
+		t.Fatalf("Read back %q", data)
+	}
+
+	err = local.Delete(ctx, "chirp.png")
+	if err != nil {
+		t.Fatalf("Failed to delete: %v", err)
+	}
+	if _, err := local.Open(ctx, "chirp.png"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestLocalRejectsPaths(t *testing.T) {
+	local, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	for _, key := range []string{"", "../secret", "a/b.png", ".hidden", ".."} {
+		if _, err := local.Open(context.Background(), key); err == nil || errors.Is(err, ErrNotFound) {
+			t.Fatalf("Expected invalid key error for %q, got %v", key, err)
+		}
+	}
+}
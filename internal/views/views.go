@@ -0,0 +1,73 @@
+package views
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Count is the number of times a chirp was viewed on one UTC day.
+type Count struct {
+	ChirpID uuid.UUID
+	Day     time.Time
+	Views   int64
+}
+
+type key struct {
+	chirpID uuid.UUID
+	day     time.Time
+}
+
+// Counter tallies chirp views in memory so that recording them adds no
+// database work to a read. The tallies are written out in bulk by whoever
+// calls Flush.
+type Counter struct {
+	mu     sync.Mutex
+	counts map[key]int64
+}
+
+func NewCounter() *Counter {
+	return &Counter{counts: map[key]int64{}}
+}
+
+// Record counts one view of each of chirpIDs at the given time.
+func (c *Counter) Record(at time.Time, chirpIDs ...uuid.UUID) {
+	day := Day(at)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, chirpID := range chirpIDs {
+		c.counts[key{chirpID: chirpID, day: day}]++
+	}
+}
+
+// Flush returns the views recorded since the last flush and starts counting
+// afresh.
+func (c *Counter) Flush() []Count {
+	c.mu.Lock()
+	counts := c.counts
+	c.counts = map[key]int64{}
+	c.mu.Unlock()
+
+	flushed := make([]Count, 0, len(counts))
+	for k, views := range counts {
+		flushed = append(flushed, Count{ChirpID: k.chirpID, Day: k.day, Views: views})
+	}
+	return flushed
+}
+
+// Restore adds counts back, for when writing out a flush failed and the
+// views should be tried again with the next one.
+func (c *Counter) Restore(counts []Count) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, count := range counts {
+		c.counts[key{chirpID: count.ChirpID, day: count.Day}] += count.Views
+	}
+}
+
+// Day truncates t to the start of its UTC day.
+func Day(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
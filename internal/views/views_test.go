@@ -0,0 +1,68 @@
+package views
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRecordAndFlush(t *testing.T) {
+	c := NewCounter()
+	a, b := uuid.New(), uuid.New()
+	monday := time.Date(2025, 3, 3, 23, 59, 0, 0, time.UTC)
+	tuesday := monday.Add(2 * time.Minute)
+
+	c.Record(monday, a, b)
+	c.Record(monday, a)
+	c.Record(tuesday, a)
+
+	counts := c.Flush()
+	slices.SortFunc(counts, func(x, y Count) int {
+		if x.ChirpID != y.ChirpID {
+			if x.ChirpID == a {
+				return -1
+			}
+			return 1
+		}
+		return x.Day.Compare(y.Day)
+	})
+	expected := []Count{
+		{ChirpID: a, Day: Day(monday), Views: 2},
+		{ChirpID: a, Day: Day(tuesday), Views: 1},
+		{ChirpID: b, Day: Day(monday), Views: 1},
+	}
+	if !slices.Equal(counts, expected) {
+		t.Fatalf("Flush() = %v, expected %v", counts, expected)
+	}
+
+	if counts := c.Flush(); len(counts) != 0 {
+		t.Fatalf("Second Flush() = %v, expected nothing", counts)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	c := NewCounter()
+	id := uuid.New()
+	now := time.Now()
+
+	c.Record(now, id)
+	failed := c.Flush()
+	c.Record(now, id)
+	c.Restore(failed)
+
+	counts := c.Flush()
+	if len(counts) != 1 || counts[0].Views != 2 {
+		t.Fatalf("Flush() after Restore = %v, expected 2 views", counts)
+	}
+}
+
+func TestDay(t *testing.T) {
+	eastern := time.FixedZone("UTC-5", -5*60*60)
+	got := Day(time.Date(2025, 3, 3, 21, 0, 0, 0, eastern))
+	expected := time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC)
+	if !got.Equal(expected) {
+		t.Fatalf("Day() = %v, expected %v", got, expected)
+	}
+}
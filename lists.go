@@ -0,0 +1,585 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"servers/internal/database"
+	"servers/internal/pagination"
+
+	"github.com/google/uuid"
+)
+
+const maxListNameLength = 50
+
+// List is a named group of accounts whose chirps can be read as one
+// timeline. Private lists are only visible to their owner; public lists can
+// be read and followed by anyone.
+type List struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UserID    uuid.UUID `json:"user_id"`
+	Name      string    `json:"name"`
+	Private   bool      `json:"private"`
+}
+
+type ListMember struct {
+	UserID  uuid.UUID `json:"user_id"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+type listRequest struct {
+	Name    string `json:"name"`
+	Private bool   `json:"private"`
+}
+
+func listFromDB(list database.List) List {
+	return List{
+		ID:        list.ID,
+		CreatedAt: list.CreatedAt,
+		UpdatedAt: list.UpdatedAt,
+		UserID:    list.UserID,
+		Name:      list.Name,
+		Private:   list.Private,
+	}
+}
+
+// validateListName trims a list's name of surrounding whitespace and checks
+// its length.
+func validateListName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if len(name) == 0 {
+		return "", errors.New("List name cannot be empty")
+	}
+	if utf8.RuneCountInString(name) > maxListNameLength {
+		return "", fmt.Errorf("List name can be at most %d characters", maxListNameLength)
+	}
+	return name, nil
+}
+
+// ownedList fetches the list in the request path for userID to change. If it
+// does not exist or is not theirs it writes the error response and returns
+// false.
+func (cfg *apiConfig) ownedList(w http.ResponseWriter, r *http.Request, userID uuid.UUID) (database.List, bool) {
+	listID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("List not found"))
+		return database.List{}, false
+	}
+	list, err := cfg.db.GetList(r.Context(), database.GetListParams{ID: listID, ViewerID: userID})
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("List not found"))
+		return database.List{}, false
+	}
+	if list.UserID != userID {
+		w.WriteHeader(403)
+		w.Write([]byte("Unauthorized"))
+		return database.List{}, false
+	}
+	return list, true
+}
+
+func (cfg *apiConfig) handlerCreateList(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	params := listRequest{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Invalid request body"})
+		w.Write(data)
+		return
+	}
+	name, err := validateListName(params.Name)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+
+	list, err := cfg.db.CreateList(r.Context(), database.CreateListParams{
+		UserID:  userID,
+		Name:    name,
+		Private: params.Private,
+	})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	w.WriteHeader(201)
+	data, _ := json.Marshal(listFromDB(list))
+	w.Write(data)
+}
+
+// handlerLists lists the caller's own lists together with the public lists
+// they follow.
+func (cfg *apiConfig) handlerLists(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	type listsResp struct {
+		Lists      []List `json:"lists"`
+		NextCursor string `json:"next_cursor,omitempty"`
+		PrevCursor string `json:"prev_cursor,omitempty"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	page, err := parsePageRequest(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+
+	cursorCreatedAt, cursorID := page.cursorArgs()
+	var lists []database.List
+	if page.ascending() {
+		lists, err = cfg.db.GetListsAscending(r.Context(), database.GetListsAscendingParams{
+			UserID:          userID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        page.rowLimit(),
+		})
+	} else {
+		lists, err = cfg.db.GetListsDescending(r.Context(), database.GetListsDescendingParams{
+			UserID:          userID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        page.rowLimit(),
+		})
+	}
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	lists, nextCursor, prevCursor := paginate(lists, page, func(list database.List) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: list.CreatedAt, ID: list.ID}
+	})
+	apiLists := make([]List, len(lists))
+	for i, list := range lists {
+		apiLists[i] = listFromDB(list)
+	}
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(listsResp{
+		Lists:      apiLists,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	})
+	w.Write(data)
+}
+
+func (cfg *apiConfig) handlerGetList(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("List not found"))
+		return
+	}
+	list, err := cfg.db.GetList(r.Context(), database.GetListParams{ID: listID, ViewerID: cfg.viewer(r)})
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("List not found"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	data, _ := json.Marshal(listFromDB(list))
+	w.Write(data)
+}
+
+func (cfg *apiConfig) handlerUpdateList(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	list, ok := cfg.ownedList(w, r, userID)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	params := listRequest{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Invalid request body"})
+		w.Write(data)
+		return
+	}
+	name, err := validateListName(params.Name)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+
+	list, err = cfg.db.UpdateList(r.Context(), database.UpdateListParams{
+		Name:    name,
+		Private: params.Private,
+		ID:      list.ID,
+	})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(listFromDB(list))
+	w.Write(data)
+}
+
+func (cfg *apiConfig) handlerDeleteList(w http.ResponseWriter, r *http.Request) {
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	list, ok := cfg.ownedList(w, r, userID)
+	if !ok {
+		return
+	}
+
+	err = cfg.db.DeleteList(r.Context(), list.ID)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerAddListMember(w http.ResponseWriter, r *http.Request) {
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	list, ok := cfg.ownedList(w, r, userID)
+	if !ok {
+		return
+	}
+
+	memberID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("user not found"))
+		return
+	}
+	_, err = cfg.db.GetUser(r.Context(), memberID)
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("user not found"))
+		return
+	}
+
+	err = cfg.db.AddListMember(r.Context(), database.AddListMemberParams{ListID: list.ID, UserID: memberID})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerRemoveListMember(w http.ResponseWriter, r *http.Request) {
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	list, ok := cfg.ownedList(w, r, userID)
+	if !ok {
+		return
+	}
+
+	memberID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("user not found"))
+		return
+	}
+
+	err = cfg.db.RemoveListMember(r.Context(), database.RemoveListMemberParams{ListID: list.ID, UserID: memberID})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerListMembers(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	type membersResp struct {
+		Users      []ListMember `json:"users"`
+		NextCursor string       `json:"next_cursor,omitempty"`
+		PrevCursor string       `json:"prev_cursor,omitempty"`
+	}
+
+	listID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("List not found"))
+		return
+	}
+	list, err := cfg.db.GetList(r.Context(), database.GetListParams{ID: listID, ViewerID: cfg.viewer(r)})
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("List not found"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	page, err := parsePageRequest(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+
+	cursorCreatedAt, cursorID := page.cursorArgs()
+	members := []ListMember{}
+	if page.ascending() {
+		var rows []database.GetListMembersAscendingRow
+		rows, err = cfg.db.GetListMembersAscending(r.Context(), database.GetListMembersAscendingParams{
+			ListID:          list.ID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        page.rowLimit(),
+		})
+		for _, row := range rows {
+			members = append(members, ListMember{UserID: row.UserID, AddedAt: row.CreatedAt})
+		}
+	} else {
+		var rows []database.GetListMembersDescendingRow
+		rows, err = cfg.db.GetListMembersDescending(r.Context(), database.GetListMembersDescendingParams{
+			ListID:          list.ID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        page.rowLimit(),
+		})
+		for _, row := range rows {
+			members = append(members, ListMember{UserID: row.UserID, AddedAt: row.CreatedAt})
+		}
+	}
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	members, nextCursor, prevCursor := paginate(members, page, func(member ListMember) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: member.AddedAt, ID: member.UserID}
+	})
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(membersResp{
+		Users:      members,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	})
+	w.Write(data)
+}
+
+func (cfg *apiConfig) handlerFollowList(w http.ResponseWriter, r *http.Request) {
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	listID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("List not found"))
+		return
+	}
+	list, err := cfg.db.GetList(r.Context(), database.GetListParams{ID: listID, ViewerID: userID})
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("List not found"))
+		return
+	}
+
+	if list.UserID == userID {
+		w.WriteHeader(400)
+		w.Write([]byte("you cannot follow your own list"))
+		return
+	}
+
+	err = cfg.db.FollowList(r.Context(), database.FollowListParams{ListID: list.ID, UserID: userID})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerUnfollowList(w http.ResponseWriter, r *http.Request) {
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	listID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("List not found"))
+		return
+	}
+
+	err = cfg.db.UnfollowList(r.Context(), database.UnfollowListParams{ListID: listID, UserID: userID})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+// handlerListChirps is the timeline of a list: the chirps of all its members
+// merged into one page, fetched with a single query however many members the
+// list has.
+func (cfg *apiConfig) handlerListChirps(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	viewerID := cfg.viewer(r)
+	listID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("List not found"))
+		return
+	}
+	list, err := cfg.db.GetList(r.Context(), database.GetListParams{ID: listID, ViewerID: viewerID})
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("List not found"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	page, err := parsePageRequest(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+
+	cursorCreatedAt, cursorID := page.cursorArgs()
+	var chirps []database.Chirp
+	if page.ascending() {
+		chirps, err = cfg.db.GetListChirpsAscending(r.Context(), database.GetListChirpsAscendingParams{
+			ListID:          list.ID,
+			ViewerID:        viewerID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        page.rowLimit(),
+		})
+	} else {
+		chirps, err = cfg.db.GetListChirpsDescending(r.Context(), database.GetListChirpsDescendingParams{
+			ListID:          list.ID,
+			ViewerID:        viewerID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        page.rowLimit(),
+		})
+	}
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	cfg.writeChirpPage(w, r, nil, chirps, page)
+}
@@ -1,19 +1,23 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
-	"sort"
-	"strings"
 	"sync/atomic"
 	"time"
 
 	"servers/internal/auth"
 	"servers/internal/database"
+	"servers/internal/filter"
+	"servers/internal/mail"
+	"servers/internal/storage"
+	"servers/internal/views"
 
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
@@ -22,37 +26,131 @@ import (
 
 type apiConfig struct {
 	fileserverHits atomic.Int32
+	dbConn         *sql.DB
 	db             *database.Queries
 	platform       string
 	secret         string
 	polkaKey       string
+	adminKey       string
+	filterMode     filter.Mode
+	editWindow     time.Duration
+	redEditWindow  time.Duration
+	trashRetention time.Duration
+	storage        storage.Storage
+	viewCounter    *views.Counter
+	mailer         mail.Mailer
 }
 
+const maxChirpLength = 140
+
 type Chirp struct {
-	ID        uuid.UUID `json:"id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	UserID    uuid.UUID `json:"user_id"`
-	Body      string    `json:"body"`
+	ID             uuid.UUID    `json:"id"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+	UserID         uuid.UUID    `json:"user_id"`
+	Body           string       `json:"body"`
+	ParentID       *uuid.UUID   `json:"parent_id"`
+	ReplyCount     int64        `json:"reply_count"`
+	LikeCount      int64        `json:"like_count"`
+	LikedByMe      bool         `json:"liked_by_me"`
+	Attachments    []Attachment `json:"attachments"`
+	PublishAt      *time.Time   `json:"publish_at,omitempty"`
+	RechirpCount   int64        `json:"rechirp_count"`
+	RechirpOf      *ChirpRef    `json:"rechirp_of,omitempty"`
+	QuoteOf        *ChirpRef    `json:"quote_of,omitempty"`
+	Poll           *Poll        `json:"poll,omitempty"`
+	ContentWarning *string      `json:"content_warning"`
+	Sensitive      bool         `json:"sensitive"`
+	Visibility     string       `json:"visibility"`
+	Collapsed      bool         `json:"collapsed"`
+	Pinned         bool         `json:"pinned"`
+	BookmarkedByMe bool         `json:"bookmarked_by_me"`
 }
 
+// User is the caller's own account. Other users' accounts are only ever
+// shown as a Profile.
 type User struct {
-	ID          uuid.UUID `json:"id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Email       string    `json:"email"`
-	IsChirpyRed bool      `json:"is_chirpy_red"`
+	ID               uuid.UUID   `json:"id"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+	Email            string      `json:"email"`
+	EmailVerified    bool        `json:"email_verified"`
+	TwoFactorEnabled bool        `json:"two_factor_enabled"`
+	IsChirpyRed      bool        `json:"is_chirpy_red"`
+	Handle           string      `json:"handle"`
+	DisplayName      string      `json:"display_name"`
+	Bio              string      `json:"bio"`
+	Location         string      `json:"location"`
+	Avatar           *Attachment `json:"avatar"`
 }
 type UserWithToken struct {
 	ID           uuid.UUID `json:"id"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 	Email        string    `json:"email"`
+	Handle       string    `json:"handle"`
 	Token        string    `json:"token"`
 	RefreshToken string    `json:"refresh_token"`
 	IsChirpyRed  bool      `json:"is_chirpy_red"`
 }
 
+// authenticate returns the ID of the user whose JWT is in the request's
+// Authorization header.
+func (cfg *apiConfig) authenticate(r *http.Request) (uuid.UUID, error) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return auth.ValidateJWT(token, cfg.secret)
+}
+
+// viewer is authenticate for endpoints that also serve anonymous callers; it
+// returns uuid.Nil when the request carries no valid JWT.
+func (cfg *apiConfig) viewer(r *http.Request) uuid.UUID {
+	if len(r.Header.Get("Authorization")) == 0 {
+		return uuid.Nil
+	}
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		return uuid.Nil
+	}
+	return userID
+}
+
+// startSession issues a fresh access token and refresh token to a user who
+// has logged in, recording the device the request came from.
+func (cfg *apiConfig) startSession(r *http.Request, user database.User) (UserWithToken, error) {
+	refreshToken, err := auth.MakeRefreshToken()
+	if err != nil {
+		return UserWithToken{}, err
+	}
+	_, err = cfg.db.AddRefreshToken(r.Context(), database.AddRefreshTokenParams{
+		Token:     refreshToken,
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(144 * time.Hour),
+		UserAgent: userAgent(r),
+		IpAddress: clientIP(r),
+	})
+	if err != nil {
+		return UserWithToken{}, err
+	}
+
+	token, err := auth.MakeJWT(user.ID, cfg.secret, time.Hour)
+	if err != nil {
+		return UserWithToken{}, err
+	}
+	return UserWithToken{
+		ID:           user.ID,
+		CreatedAt:    user.CreatedAt,
+		UpdatedAt:    user.UpdatedAt,
+		Email:        user.Email,
+		Handle:       user.Handle,
+		Token:        token,
+		RefreshToken: refreshToken,
+		IsChirpyRed:  user.IsChirpyRed,
+	}, nil
+}
+
 func (cfg *apiConfig) middlewareMetricsInc(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		cfg.fileserverHits.Add(1)
@@ -85,37 +183,90 @@ func (cfg *apiConfig) resetHandler(w http.ResponseWriter, r *http.Request) {
 	cfg.fileserverHits.Store(0)
 }
 
-func clean(text string) string {
-	textArr := strings.Split(text, " ")
-	checkArr := []string{"kerfuffle", "sharbert", "fornax"}
-	for i, word := range textArr {
-		for _, checkWord := range checkArr {
-			if word == checkWord || strings.ToLower(word) == checkWord {
-				textArr[i] = "****"
-			}
-		}
-	}
-	return strings.Join(textArr, " ")
-}
-
 func main() {
 	godotenv.Load()
 	dbURL := os.Getenv("DB_URL")
 	platform := os.Getenv("PLATFORM")
 	secret := os.Getenv("SECRET")
 	polkaKey := os.Getenv("POLKA_KEY")
+	// EDIT_WINDOW limits how long chirps stay editable; Chirpy Red users get
+	// CHIRPY_RED_EDIT_WINDOW instead, which is unlimited when unset.
+	editWindow, _ := time.ParseDuration(os.Getenv("EDIT_WINDOW"))
+	redEditWindow, _ := time.ParseDuration(os.Getenv("CHIRPY_RED_EDIT_WINDOW"))
+	trashRetention, err := time.ParseDuration(os.Getenv("TRASH_RETENTION"))
+	if err != nil || trashRetention <= 0 {
+		trashRetention = defaultTrashRetention
+	}
+	adminKey := os.Getenv("ADMIN_KEY")
+	mediaDir := os.Getenv("MEDIA_DIR")
+	if len(mediaDir) == 0 {
+		mediaDir = "media"
+	}
+	mediaStorage, err := storage.NewLocal(mediaDir)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	// Email goes out over SMTP when SMTP_HOST is set, and is written to
+	// MAIL_DIR otherwise so that it can be read during development.
+	mailFrom := os.Getenv("MAIL_FROM")
+	if len(mailFrom) == 0 {
+		mailFrom = "no-reply@localhost"
+	}
+	var mailer mail.Mailer
+	if smtpHost := os.Getenv("SMTP_HOST"); len(smtpHost) > 0 {
+		smtpPort := os.Getenv("SMTP_PORT")
+		if len(smtpPort) == 0 {
+			smtpPort = "587"
+		}
+		mailer = mail.NewSMTP(smtpHost, smtpPort, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), mailFrom)
+	} else {
+		mailDir := os.Getenv("MAIL_DIR")
+		if len(mailDir) == 0 {
+			mailDir = "mail"
+		}
+		mailer, err = mail.NewDir(mailDir, mailFrom)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+	}
 	db, _ := sql.Open("postgres", dbURL)
 	dbQueries := database.New(db)
+
+	filterMode, err := filter.ParseMode(os.Getenv("FILTER_MODE"))
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if wordsFile := os.Getenv("FILTER_WORDS_FILE"); len(wordsFile) > 0 {
+		err = seedFilterWords(context.Background(), dbQueries, wordsFile)
+		if err != nil {
+			log.Printf("Error loading filter words: %v", err)
+		}
+	}
 	mux := http.NewServeMux()
 
 	apiCfg := apiConfig{
-		db:       dbQueries,
-		platform: platform,
-		secret:   secret,
-		polkaKey: polkaKey,
+		dbConn:         db,
+		db:             dbQueries,
+		platform:       platform,
+		secret:         secret,
+		polkaKey:       polkaKey,
+		adminKey:       adminKey,
+		filterMode:     filterMode,
+		editWindow:     editWindow,
+		redEditWindow:  redEditWindow,
+		trashRetention: trashRetention,
+		storage:        mediaStorage,
+		viewCounter:    views.NewCounter(),
+		mailer:         mailer,
 	}
 
+	go apiCfg.runPurger(context.Background())
+	go apiCfg.runPublisher(context.Background())
+	go apiCfg.runViewFlusher(context.Background())
+
 	mux.Handle("/app/", http.StripPrefix("/app", apiCfg.middlewareMetricsInc(http.FileServer(http.Dir(".")))))
+	mux.HandleFunc("GET /media/{key}", apiCfg.handlerServeMedia)
+	mux.HandleFunc("POST /api/media", apiCfg.handlerUploadMedia)
 	mux.HandleFunc("GET /api/healthz", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 		w.WriteHeader(200)
@@ -146,30 +297,36 @@ func main() {
 			return
 		}
 
-		refreshToken, _ := auth.MakeRefreshToken()
-
-		apiCfg.db.AddRefreshToken(r.Context(), database.AddRefreshTokenParams{refreshToken, user.ID, time.Now().Add(144 * time.Hour)})
+		if user.TotpEnabled {
+			challengeToken, err := apiCfg.startLoginChallenge(r.Context(), user.ID)
+			if err != nil {
+				log.Printf("%v", err)
+				w.WriteHeader(500)
+				w.Write([]byte("Server Error - something went wrong"))
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			data, _ := json.Marshal(loginChallenge{TwoFactorRequired: true, ChallengeToken: challengeToken})
+			w.Write(data)
+			return
+		}
 
-		// JWT token
-		tokenExpiry := 3600
-		token, _ := auth.MakeJWT(user.ID, apiCfg.secret, time.Duration(tokenExpiry*int(time.Second)))
-		userToReturn := UserWithToken{
-			ID:           user.ID,
-			CreatedAt:    user.CreatedAt,
-			UpdatedAt:    user.UpdatedAt,
-			Email:        user.Email,
-			Token:        token,
-			RefreshToken: refreshToken,
-			IsChirpyRed:  user.IsChirpyRed,
+		userToReturn, err := apiCfg.startSession(r, user)
+		if err != nil {
+			log.Printf("%v", err)
+			w.WriteHeader(500)
+			w.Write([]byte("Server Error - something went wrong"))
+			return
 		}
 
 		w.WriteHeader(200)
 		data, _ := json.Marshal(userToReturn)
 		w.Write(data)
 	})
+	mux.HandleFunc("POST /api/login/2fa", apiCfg.handlerLoginTwoFactor)
 	mux.HandleFunc("POST /api/refresh", func(w http.ResponseWriter, r *http.Request) {
 		// validing jwt
-		log.Printf("***: %v", r.Header.Get("Authorization"))
 		token, tokenErr := auth.GetBearerToken(r.Header)
 		if tokenErr != nil {
 			log.Printf("%v", tokenErr)
@@ -194,6 +351,10 @@ func main() {
 			w.Write([]byte("invalid token - revoked"))
 			return
 		}
+		err = apiCfg.db.TouchRefreshToken(r.Context(), database.TouchRefreshTokenParams{IpAddress: clientIP(r), Token: token})
+		if err != nil {
+			log.Printf("Error updating session: %v", err)
+		}
 		accessToken, _ := auth.MakeJWT(refreshToken.UserID, apiCfg.secret, time.Duration(3600*time.Second))
 
 		type tokenResponse struct {
@@ -221,159 +382,143 @@ func main() {
 		w.WriteHeader(204)
 		w.Write([]byte("token revoked"))
 	})
-	mux.HandleFunc("PUT /api/users", func(w http.ResponseWriter, r *http.Request) {
-		type parameters struct {
-			Email    string `json:"email"`
-			Password string `json:"password"`
-		}
-
-		token, tokenErr := auth.GetBearerToken(r.Header)
-		if tokenErr != nil {
-			log.Printf("%v", tokenErr)
-			w.WriteHeader(401)
-			w.Write([]byte("invalid token"))
-			return
-		}
-
-		userID, validationErr := auth.ValidateJWT(token, apiCfg.secret)
-		if validationErr != nil {
-			log.Printf("%v", validationErr)
-			w.WriteHeader(401)
-			w.Write([]byte("invalid token"))
-			return
-		}
-
-		params := parameters{}
-		decoder := json.NewDecoder(r.Body)
-		err := decoder.Decode(&params)
-
+	mux.HandleFunc("PUT /api/users", apiCfg.handlerUpdateUser)
+	mux.HandleFunc("GET /admin/metrics", apiCfg.metricsHandler)
+	mux.HandleFunc("POST /admin/reset", apiCfg.resetHandler)
+	mux.HandleFunc("GET /admin/filter/words", apiCfg.handlerFilterWords)
+	mux.HandleFunc("POST /admin/filter/words", apiCfg.handlerAddFilterWords)
+	mux.HandleFunc("DELETE /admin/filter/words/{word}", apiCfg.handlerDeleteFilterWord)
+	mux.HandleFunc("GET /admin/filter/flags", apiCfg.handlerModerationFlags)
+	mux.HandleFunc("PUT /admin/chirps/{ID}/content-warning", apiCfg.handlerSetContentWarning)
+	mux.HandleFunc("POST /admin/filter/flags/{ID}/resolve", apiCfg.handlerResolveModerationFlag)
+	mux.HandleFunc("GET /admin/users/{ID}/sessions", apiCfg.handlerAdminSessions)
+	mux.HandleFunc("POST /admin/users/{ID}/sessions/revoke-all", apiCfg.handlerAdminRevokeAllSessions)
+	mux.HandleFunc("GET /api/chirps", func(w http.ResponseWriter, r *http.Request) {
 		type errorResp struct {
 			Error string `json:"error"`
 		}
 
+		w.Header().Set("Content-Type", "application/json")
+		page, err := parsePageRequest(r.URL.Query())
 		if err != nil {
-			errorResponse := errorResp{
-				Error: "Invalid params",
-			}
 			w.WriteHeader(400)
-			data, _ := json.Marshal(errorResponse)
+			data, _ := json.Marshal(errorResp{Error: err.Error()})
 			w.Write(data)
 			return
 		}
 
-		hashedPassword, err := auth.HashPassword(params.Password)
-		if err != nil {
-			w.WriteHeader(500)
-			w.Write([]byte("Something went wrong while hashing password"))
+		authorID := uuid.Nil
+		authorIDFromQuery := r.URL.Query().Get("author_id")
+		if len(authorIDFromQuery) > 0 {
+			authorID, err = uuid.Parse(authorIDFromQuery)
+			if err != nil {
+				w.WriteHeader(400)
+				data, _ := json.Marshal(errorResp{Error: "invalid author_id"})
+				w.Write(data)
+				return
+			}
 		}
 
-		// creating the new user in DB
-		user, err := apiCfg.db.UpdateUser(r.Context(), database.UpdateUserParams{Email: params.Email, HashedPassword: hashedPassword, ID: userID})
+		hideSensitive := r.URL.Query().Get("hide_sensitive") == "true"
+		viewerID := apiCfg.viewer(r)
+
+		cursorCreatedAt, cursorID := page.cursorArgs()
+		var chirps []database.Chirp
+		if page.ascending() {
+			chirps, err = apiCfg.db.GetChirpsAscending(r.Context(), database.GetChirpsAscendingParams{
+				ViewerID:        viewerID,
+				HideSensitive:   hideSensitive,
+				AuthorID:        authorID,
+				CursorCreatedAt: cursorCreatedAt,
+				CursorID:        cursorID,
+				RowLimit:        page.rowLimit(),
+			})
+		} else {
+			chirps, err = apiCfg.db.GetChirpsDescending(r.Context(), database.GetChirpsDescendingParams{
+				ViewerID:        viewerID,
+				HideSensitive:   hideSensitive,
+				AuthorID:        authorID,
+				CursorCreatedAt: cursorCreatedAt,
+				CursorID:        cursorID,
+				RowLimit:        page.rowLimit(),
+			})
+		}
 		if err != nil {
-			errorResponse := errorResp{
-				Error: fmt.Sprintf("Error in updating the user: %v", err),
-			}
+			log.Printf("%v", err)
 			w.WriteHeader(500)
-			data, _ := json.Marshal(errorResponse)
+			data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
 			w.Write(data)
 			return
 		}
 
-		userToReturn := User{
-			ID:        user.ID,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
-			Email:     user.Email,
-		}
-
-		w.WriteHeader(200)
-		data, _ := json.Marshal(userToReturn)
-		w.Write(data)
-	})
-	mux.HandleFunc("GET /admin/metrics", apiCfg.metricsHandler)
-	mux.HandleFunc("POST /admin/reset", apiCfg.resetHandler)
-	mux.HandleFunc("GET /api/chirps", func(w http.ResponseWriter, r *http.Request) {
-		authorIDFromQuery := r.URL.Query().Get("author_id")
-		sortParam := r.URL.Query().Get("sort")
-
-		var chirps []database.Chirp
-		if len(authorIDFromQuery) == 0 {
-			chirpsFromDB, err := apiCfg.db.GetChirps(r.Context(), uuid.Nil)
+		// an author's pinned chirps lead the first page of their listing
+		// and are left out of the rest of it
+		var pinned []database.Chirp
+		if authorID != uuid.Nil && page.cursor == nil {
+			pinned, err = apiCfg.db.GetPinnedChirps(r.Context(), database.GetPinnedChirpsParams{UserID: authorID, ViewerID: viewerID, HideSensitive: hideSensitive})
 			if err != nil {
 				log.Printf("%v", err)
+				w.WriteHeader(500)
+				data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+				w.Write(data)
+				return
 			}
-			chirps = chirpsFromDB
-		} else {
-			authorID, _ := uuid.Parse(authorIDFromQuery)
-			chirps, _ = apiCfg.db.GetChirps(r.Context(), authorID)
-		}
-		apiChirps := make([]Chirp, len(chirps))
-		for i, chirp := range chirps {
-			apiChirps[i] = Chirp{
-				ID:        chirp.ID,
-				CreatedAt: chirp.CreatedAt,
-				UpdatedAt: chirp.UpdatedAt,
-				Body:      chirp.Body,
-				UserID:    chirp.UserID,
-			}
-		}
-
-		log.Printf("%v", chirps)
-		log.Printf("%v", apiChirps)
-
-		if sortParam == "desc" {
-			sort.Slice(apiChirps, func(i, j int) bool { return apiChirps[i].CreatedAt.After(apiChirps[j].CreatedAt) })
-		} else {
-			sort.Slice(apiChirps, func(i, j int) bool { return apiChirps[i].CreatedAt.Before(apiChirps[j].CreatedAt) })
 		}
 
-		w.WriteHeader(200)
-		data, _ := json.Marshal(apiChirps)
-		w.Write(data)
+		apiCfg.writeChirpPage(w, r, pinned, chirps, page)
 	})
+	mux.HandleFunc("GET /api/users/me/analytics", apiCfg.handlerAnalytics)
 	mux.HandleFunc("GET /api/chirps/{ID}", func(w http.ResponseWriter, r *http.Request) {
 		chirpID := r.PathValue("ID")
 		UUID, _ := uuid.Parse(chirpID)
-		chirp, err := apiCfg.db.GetChirp(r.Context(), UUID)
+		viewerID := apiCfg.viewer(r)
+		chirp, err := apiCfg.db.GetChirp(r.Context(), database.GetChirpParams{ID: UUID, ViewerID: viewerID})
 		if err != nil {
 			w.WriteHeader(404)
 			w.Write([]byte("Chirp not found"))
+			return
 		}
 
-		chirpToReturn := Chirp{
-			ID:        chirp.ID,
-			CreatedAt: chirp.CreatedAt,
-			UpdatedAt: chirp.UpdatedAt,
-			Body:      chirp.Body,
-			UserID:    chirp.UserID,
+		chirpsToReturn, err := apiCfg.hydrateChirps(r.Context(), viewerID, []database.Chirp{chirp})
+		if err != nil {
+			log.Printf("%v", err)
+			w.WriteHeader(500)
+			w.Write([]byte("Server Error - something went wrong"))
+			return
 		}
+		apiCfg.recordViews(viewerID, chirpsToReturn)
 
-		data, _ := json.Marshal(chirpToReturn)
+		data, _ := json.Marshal(chirpsToReturn[0])
 		w.WriteHeader(200)
 		w.Write(data)
 	})
+	mux.HandleFunc("GET /api/chirps/search", apiCfg.handlerSearchChirps)
+	mux.HandleFunc("GET /api/chirps/{ID}/thread", apiCfg.handlerChirpThread)
+	mux.HandleFunc("PUT /api/chirps/{ID}", apiCfg.handlerEditChirp)
+	mux.HandleFunc("GET /api/chirps/{ID}/revisions", apiCfg.handlerChirpRevisions)
+	mux.HandleFunc("POST /api/chirps/{ID}/restore", apiCfg.handlerRestoreChirp)
+	mux.HandleFunc("POST /api/chirps/{ID}/rechirp", apiCfg.handlerRechirp)
+	mux.HandleFunc("DELETE /api/chirps/{ID}/rechirp", apiCfg.handlerUndoRechirp)
+	mux.HandleFunc("POST /api/chirps/{ID}/bookmark", apiCfg.handlerBookmarkChirp)
+	mux.HandleFunc("DELETE /api/chirps/{ID}/bookmark", apiCfg.handlerUnbookmarkChirp)
+	mux.HandleFunc("POST /api/chirps/{ID}/pin", apiCfg.handlerPinChirp)
+	mux.HandleFunc("DELETE /api/chirps/{ID}/pin", apiCfg.handlerUnpinChirp)
+	mux.HandleFunc("POST /api/chirps/{ID}/vote", apiCfg.handlerVoteInPoll)
+	mux.HandleFunc("POST /api/chirps/{ID}/like", apiCfg.handlerLikeChirp)
+	mux.HandleFunc("DELETE /api/chirps/{ID}/like", apiCfg.handlerUnlikeChirp)
+	mux.HandleFunc("GET /api/timeline", apiCfg.handlerTimeline)
 	mux.HandleFunc("POST /api/chirps", func(w http.ResponseWriter, r *http.Request) {
-		type parameters struct {
-			Body string `json:"body"`
-		}
-
 		type errorResp struct {
 			Error string `json:"error"`
 		}
 
-		type cleanedResp struct {
-			CleanedBody string `json:"cleaned_body"`
-		}
-
-		decoder := json.NewDecoder(r.Body)
-		params := parameters{}
-		err := decoder.Decode(&params)
-
-		// validing jwt
+		// validing jwt before reading the body, so that anonymous clients
+		// cannot make the server buffer uploads
 
 		token, tokenErr := auth.GetBearerToken(r.Header)
 		if tokenErr != nil {
 			log.Printf("%v", tokenErr)
+			w.WriteHeader(401)
 			w.Write([]byte("invalid token"))
 			return
 		}
@@ -387,42 +532,200 @@ func main() {
 		}
 
 		w.Header().Set("Content-Type", "application/json")
+		user, err := apiCfg.db.GetUser(r.Context(), userID)
 		if err != nil {
+			log.Printf("%v", err)
 			w.WriteHeader(500)
+			data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+			w.Write(data)
+			return
+		}
+		if !user.EmailVerified {
+			w.WriteHeader(403)
+			data, _ := json.Marshal(errorResp{Error: "Verify your email address before posting chirps"})
+			w.Write(data)
+			return
+		}
+
+		params, err := decodeCreateChirpRequest(w, r)
+		if err != nil {
+			w.WriteHeader(400)
 			errorResponse := errorResp{
-				Error: "Something went wrong",
+				Error: "Invalid request body",
 			}
 			data, _ := json.Marshal(errorResponse)
 			w.Write(data)
 			return
 		}
 
-		isValid := len(params.Body) <= 140
+		chirpFilter, err := apiCfg.chirpFilter(r.Context())
+		if err != nil {
+			log.Printf("%v", err)
+			w.WriteHeader(500)
+			data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+			w.Write(data)
+			return
+		}
+		body, flagged, err := validateChirpBody(chirpFilter, params.Body)
+		if err != nil {
+			w.WriteHeader(400)
+			errorResponse := errorResp{
+				Error: err.Error(),
+			}
+			data, _ := json.Marshal(errorResponse)
+			w.Write(data)
+			return
+		}
 
-		if !isValid {
+		if len(params.MediaIDs)+len(params.uploads) > maxAttachmentsPerChirp {
 			w.WriteHeader(400)
 			errorResponse := errorResp{
-				Error: "Chirp is too long",
+				Error: fmt.Sprintf("A chirp can have at most %d attachments", maxAttachmentsPerChirp),
 			}
 			data, _ := json.Marshal(errorResponse)
 			w.Write(data)
 			return
 		}
 
-		chirp, err := apiCfg.db.CreateChirp(r.Context(), database.CreateChirpParams{Body: params.Body, UserID: userID})
-		chirpToReturn := Chirp{
-			ID:        chirp.ID,
-			CreatedAt: chirp.CreatedAt,
-			UpdatedAt: chirp.UpdatedAt,
-			UserID:    chirp.UserID,
-			Body:      chirp.Body,
+		contentWarning, err := validateContentWarning(params.ContentWarning)
+		if err != nil {
+			w.WriteHeader(400)
+			errorResponse := errorResp{
+				Error: err.Error(),
+			}
+			data, _ := json.Marshal(errorResponse)
+			w.Write(data)
+			return
 		}
 
-		w.WriteHeader(201)
-		//cleanedResponse := cleanedResp{
-		//	CleanedBody: clean(params.Body),
-		//}
+		visibility, err := validateVisibility(params.Visibility)
+		if err != nil {
+			w.WriteHeader(400)
+			errorResponse := errorResp{
+				Error: err.Error(),
+			}
+			data, _ := json.Marshal(errorResponse)
+			w.Write(data)
+			return
+		}
+
+		var poll *newPoll
+		if params.Poll != nil {
+			poll, err = validatePoll(*params.Poll)
+			if err != nil {
+				w.WriteHeader(400)
+				errorResponse := errorResp{
+					Error: err.Error(),
+				}
+				data, _ := json.Marshal(errorResponse)
+				w.Write(data)
+				return
+			}
+		}
+
+		publishAt := sql.NullTime{}
+		if params.PublishAt != nil {
+			if !params.PublishAt.After(time.Now()) {
+				w.WriteHeader(400)
+				errorResponse := errorResp{
+					Error: "publish_at must be in the future",
+				}
+				data, _ := json.Marshal(errorResponse)
+				w.Write(data)
+				return
+			}
+			publishAt = sql.NullTime{Time: params.PublishAt.UTC(), Valid: true}
+		}
+
+		parentID := uuid.NullUUID{}
+		if params.InReplyTo != nil {
+			parent, err := apiCfg.db.GetChirp(r.Context(), database.GetChirpParams{ID: *params.InReplyTo, ViewerID: userID})
+			if err != nil {
+				w.WriteHeader(400)
+				errorResponse := errorResp{
+					Error: "Chirp being replied to does not exist",
+				}
+				data, _ := json.Marshal(errorResponse)
+				w.Write(data)
+				return
+			}
+			parentID = uuid.NullUUID{UUID: originalID(parent), Valid: true}
+		}
 
+		quoteOf := uuid.NullUUID{}
+		if params.QuoteOf != nil {
+			quoted, err := apiCfg.db.GetChirp(r.Context(), database.GetChirpParams{ID: *params.QuoteOf, ViewerID: userID})
+			if err != nil {
+				w.WriteHeader(400)
+				errorResponse := errorResp{
+					Error: "Chirp being quoted does not exist",
+				}
+				data, _ := json.Marshal(errorResponse)
+				w.Write(data)
+				return
+			}
+			quoteOf = uuid.NullUUID{UUID: originalID(quoted), Valid: true}
+		}
+
+		mediaIDs := params.MediaIDs
+		for _, upload := range params.uploads {
+			attachment, err := apiCfg.storeUpload(r.Context(), userID, upload)
+			if errors.Is(err, errInvalidUpload) {
+				w.WriteHeader(400)
+				errorResponse := errorResp{
+					Error: err.Error(),
+				}
+				data, _ := json.Marshal(errorResponse)
+				w.Write(data)
+				return
+			}
+			if err != nil {
+				log.Printf("%v", err)
+				w.WriteHeader(500)
+				errorResponse := errorResp{
+					Error: "Something went wrong",
+				}
+				data, _ := json.Marshal(errorResponse)
+				w.Write(data)
+				return
+			}
+			mediaIDs = append(mediaIDs, attachment.ID)
+		}
+
+		chirp, err := apiCfg.createChirp(r.Context(), database.CreateChirpParams{Body: body, UserID: userID, ParentID: parentID, PublishAt: publishAt, QuoteOf: quoteOf, ContentWarning: contentWarning, Sensitive: params.Sensitive, Visibility: visibility}, flagged, mediaIDs, poll)
+		if errors.Is(err, errMediaNotFound) {
+			w.WriteHeader(400)
+			errorResponse := errorResp{
+				Error: err.Error(),
+			}
+			data, _ := json.Marshal(errorResponse)
+			w.Write(data)
+			return
+		}
+		if err != nil {
+			log.Printf("%v", err)
+			w.WriteHeader(500)
+			errorResponse := errorResp{
+				Error: "Something went wrong",
+			}
+			data, _ := json.Marshal(errorResponse)
+			w.Write(data)
+			return
+		}
+		chirpsToReturn, err := apiCfg.hydrateChirps(r.Context(), userID, []database.Chirp{chirp})
+		if err != nil {
+			log.Printf("%v", err)
+			w.WriteHeader(500)
+			errorResponse := errorResp{
+				Error: "Something went wrong",
+			}
+			data, _ := json.Marshal(errorResponse)
+			w.Write(data)
+			return
+		}
+		chirpToReturn := chirpsToReturn[0]
+
+		w.WriteHeader(201)
 		data, _ := json.Marshal(chirpToReturn)
 		w.Write(data)
 	})
@@ -447,8 +750,8 @@ func main() {
 		// fetching the chirp
 		chirpID := r.PathValue("ID")
 		UUID, _ := uuid.Parse(chirpID)
-		chirp, err := apiCfg.db.GetChirp(r.Context(), UUID)
-		if err != nil {
+		chirp, err := apiCfg.db.GetChirpIncludingScheduled(r.Context(), UUID)
+		if err != nil || (chirp.PublishAt.Valid && chirp.UserID != userID) {
 			w.WriteHeader(404)
 			w.Write([]byte("Chirp not found"))
 			return
@@ -517,10 +820,55 @@ func main() {
 		w.Write(dataToReturn)
 	})
 
+	mux.HandleFunc("GET /api/notifications", apiCfg.handlerNotifications)
+	mux.HandleFunc("POST /api/notifications/read", apiCfg.handlerNotificationsRead)
+
+	mux.HandleFunc("GET /api/tags/trending", apiCfg.handlerTrendingTags)
+	mux.HandleFunc("GET /api/tags/{tag}/chirps", apiCfg.handlerTagChirps)
+
+	mux.HandleFunc("POST /api/drafts", apiCfg.handlerCreateDraft)
+	mux.HandleFunc("GET /api/drafts", apiCfg.handlerDrafts)
+	mux.HandleFunc("GET /api/drafts/{ID}", apiCfg.handlerGetDraft)
+	mux.HandleFunc("PUT /api/drafts/{ID}", apiCfg.handlerUpdateDraft)
+	mux.HandleFunc("DELETE /api/drafts/{ID}", apiCfg.handlerDeleteDraft)
+	mux.HandleFunc("POST /api/drafts/{ID}/publish", apiCfg.handlerPublishDraft)
+	mux.HandleFunc("GET /api/users/me/trash", apiCfg.handlerTrash)
+	mux.HandleFunc("GET /api/users/me/preferences", apiCfg.handlerPreferences)
+	mux.HandleFunc("PUT /api/users/me/preferences", apiCfg.handlerUpdatePreferences)
+	mux.HandleFunc("GET /api/users/me/bookmarks", apiCfg.handlerBookmarks)
+	mux.HandleFunc("GET /api/users/me/scheduled", apiCfg.handlerScheduledChirps)
+	mux.HandleFunc("POST /api/lists", apiCfg.handlerCreateList)
+	mux.HandleFunc("GET /api/lists", apiCfg.handlerLists)
+	mux.HandleFunc("GET /api/lists/{ID}", apiCfg.handlerGetList)
+	mux.HandleFunc("PUT /api/lists/{ID}", apiCfg.handlerUpdateList)
+	mux.HandleFunc("DELETE /api/lists/{ID}", apiCfg.handlerDeleteList)
+	mux.HandleFunc("GET /api/lists/{ID}/members", apiCfg.handlerListMembers)
+	mux.HandleFunc("PUT /api/lists/{ID}/members/{userID}", apiCfg.handlerAddListMember)
+	mux.HandleFunc("DELETE /api/lists/{ID}/members/{userID}", apiCfg.handlerRemoveListMember)
+	mux.HandleFunc("POST /api/lists/{ID}/follow", apiCfg.handlerFollowList)
+	mux.HandleFunc("DELETE /api/lists/{ID}/follow", apiCfg.handlerUnfollowList)
+	mux.HandleFunc("GET /api/lists/{ID}/chirps", apiCfg.handlerListChirps)
+	mux.HandleFunc("POST /api/users/me/2fa/enroll", apiCfg.handlerEnrollTwoFactor)
+	mux.HandleFunc("POST /api/users/me/2fa/confirm", apiCfg.handlerConfirmTwoFactor)
+	mux.HandleFunc("POST /api/users/me/2fa/disable", apiCfg.handlerDisableTwoFactor)
+	mux.HandleFunc("GET /api/sessions", apiCfg.handlerSessions)
+	mux.HandleFunc("DELETE /api/sessions/{ID}", apiCfg.handlerRevokeSession)
+	mux.HandleFunc("POST /api/sessions/revoke-all", apiCfg.handlerRevokeAllSessions)
+	mux.HandleFunc("POST /api/password/forgot", apiCfg.handlerForgotPassword)
+	mux.HandleFunc("POST /api/password/reset", apiCfg.handlerResetPassword)
+	mux.HandleFunc("POST /api/users/verify", apiCfg.handlerVerifyEmail)
+	mux.HandleFunc("POST /api/users/verify/resend", apiCfg.handlerResendVerification)
+	mux.HandleFunc("GET /api/users/{handle}", apiCfg.handlerProfile)
+	mux.HandleFunc("POST /api/users/{ID}/follow", apiCfg.handlerFollow)
+	mux.HandleFunc("DELETE /api/users/{ID}/follow", apiCfg.handlerUnfollow)
+	mux.HandleFunc("GET /api/users/{ID}/followers", apiCfg.handlerFollowers)
+	mux.HandleFunc("GET /api/users/{ID}/following", apiCfg.handlerFollowing)
+
 	mux.HandleFunc("POST /api/users", func(w http.ResponseWriter, r *http.Request) {
 		type parameters struct {
 			Email    string `json:"email"`
 			Password string `json:"password"`
+			Handle   string `json:"handle"`
 		}
 
 		params := parameters{}
@@ -541,6 +889,38 @@ func main() {
 			return
 		}
 
+		var handle string
+		if len(params.Handle) > 0 {
+			handle, err = validateHandle(params.Handle)
+			if err != nil {
+				errorResponse := errorResp{
+					Error: err.Error(),
+				}
+				w.WriteHeader(400)
+				data, _ := json.Marshal(errorResponse)
+				w.Write(data)
+				return
+			}
+			_, err = apiCfg.db.GetUserByHandle(r.Context(), handle)
+			if err == nil {
+				errorResponse := errorResp{
+					Error: "Handle already taken",
+				}
+				w.WriteHeader(409)
+				data, _ := json.Marshal(errorResponse)
+				w.Write(data)
+				return
+			}
+		} else {
+			handle, err = apiCfg.defaultHandle(r.Context(), params.Email)
+			if err != nil {
+				log.Printf("%v", err)
+				w.WriteHeader(500)
+				w.Write([]byte("Server Error - something went wrong"))
+				return
+			}
+		}
+
 		hashedPassword, err := auth.HashPassword(params.Password)
 		if err != nil {
 			w.WriteHeader(500)
@@ -548,22 +928,35 @@ func main() {
 		}
 
 		// creating the new user in DB
-		user, err := apiCfg.db.CreateUser(r.Context(), database.CreateUserParams{Email: params.Email, HashedPassword: hashedPassword})
-		if err != nil {
+		user, err := apiCfg.db.CreateUser(r.Context(), database.CreateUserParams{Email: params.Email, HashedPassword: hashedPassword, Handle: handle})
+		if isUniqueViolation(err) {
 			errorResponse := errorResp{
-				Error: fmt.Sprintf("Error in creating the user: %v", err),
+				Error: "Email or handle already taken",
 			}
-			w.WriteHeader(500)
+			w.WriteHeader(409)
 			data, _ := json.Marshal(errorResponse)
 			w.Write(data)
 			return
 		}
+		if err != nil {
+			log.Printf("%v", err)
+			w.WriteHeader(500)
+			w.Write([]byte("Server Error - something went wrong"))
+			return
+		}
 
-		userToReturn := User{
-			ID:        user.ID,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
-			Email:     user.Email,
+		// the account exists either way; the user can ask for another email
+		err = apiCfg.sendVerification(r.Context(), user)
+		if err != nil {
+			log.Printf("Error sending verification email: %v", err)
+		}
+
+		userToReturn, err := apiCfg.userFromDB(r.Context(), user)
+		if err != nil {
+			log.Printf("%v", err)
+			w.WriteHeader(500)
+			w.Write([]byte("Server Error - something went wrong"))
+			return
 		}
 
 		w.WriteHeader(201)
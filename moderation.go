@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"servers/internal/auth"
+	"servers/internal/database"
+	"servers/internal/filter"
+
+	"github.com/google/uuid"
+)
+
+type ModerationFlag struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	ChirpID   uuid.UUID `json:"chirp_id"`
+	Words     []string  `json:"words"`
+}
+
+// seedFilterWords adds the words in wordsFile to the filter_words table.
+func seedFilterWords(ctx context.Context, db *database.Queries, wordsFile string) error {
+	file, err := os.Open(wordsFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	words, err := filter.ReadWords(file)
+	if err != nil {
+		return err
+	}
+	return db.AddFilterWords(ctx, words)
+}
+
+// chirpFilter builds the chirp filter from the filter_words table. The table
+// is read every time so that admin edits take effect at once on every
+// instance.
+func (cfg *apiConfig) chirpFilter(ctx context.Context) (*filter.Filter, error) {
+	words, err := cfg.db.GetFilterWords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return filter.New(cfg.filterMode, words), nil
+}
+
+// validateChirpBody runs the checks every new or edited chirp body has to
+// pass. It returns the body to store, which is masked in mask mode, and the
+// filtered words to flag the chirp with in flag mode. The error message is
+// meant for the client. The length limit applies to the body as written, the
+// same as for drafts.
+func validateChirpBody(chirpFilter *filter.Filter, body string) (string, []string, error) {
+	if len(body) > maxChirpLength {
+		return "", nil, fmt.Errorf("Chirp is too long")
+	}
+
+	var flagged []string
+	result := chirpFilter.Check(body)
+	if len(result.Matches) > 0 {
+		switch chirpFilter.Mode() {
+		case filter.ModeReject:
+			return "", nil, fmt.Errorf("Chirp contains words that are not allowed")
+		case filter.ModeFlag:
+			flagged = result.Matches
+		default:
+			body = result.Masked
+		}
+	}
+	return body, flagged, nil
+}
+
+func flagChirp(ctx context.Context, qtx *database.Queries, chirp database.Chirp, words []string) error {
+	if len(words) == 0 {
+		return nil
+	}
+	return qtx.CreateModerationFlag(ctx, database.CreateModerationFlagParams{ChirpID: chirp.ID, Words: words})
+}
+
+// authenticateAdmin checks the request carries ADMIN_KEY the same way polka
+// webhooks carry POLKA_KEY. Admin endpoints are disabled when it is unset.
+func (cfg *apiConfig) authenticateAdmin(r *http.Request) bool {
+	apiKey, err := auth.GetAPIKey(r.Header)
+	if err != nil {
+		return false
+	}
+	return len(cfg.adminKey) > 0 && apiKey == cfg.adminKey
+}
+
+func (cfg *apiConfig) handlerFilterWords(w http.ResponseWriter, r *http.Request) {
+	type filterResp struct {
+		Mode  filter.Mode `json:"mode"`
+		Words []string    `json:"words"`
+	}
+
+	if !cfg.authenticateAdmin(r) {
+		w.WriteHeader(401)
+		w.Write([]byte("API key incorrect"))
+		return
+	}
+
+	words, err := cfg.db.GetFilterWords(r.Context())
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	data, _ := json.Marshal(filterResp{
+		Mode:  cfg.filterMode,
+		Words: words,
+	})
+	w.Write(data)
+}
+
+func (cfg *apiConfig) handlerAddFilterWords(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Words []string `json:"words"`
+	}
+
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	if !cfg.authenticateAdmin(r) {
+		w.WriteHeader(401)
+		w.Write([]byte("API key incorrect"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Invalid params"})
+		w.Write(data)
+		return
+	}
+
+	words := make([]string, len(params.Words))
+	for i, word := range params.Words {
+		words[i] = filter.NormalizeWord(word)
+		if len(words[i]) == 0 {
+			w.WriteHeader(400)
+			data, _ := json.Marshal(errorResp{Error: fmt.Sprintf("invalid filter word: %v", word)})
+			w.Write(data)
+			return
+		}
+	}
+
+	err = cfg.db.AddFilterWords(r.Context(), words)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerDeleteFilterWord(w http.ResponseWriter, r *http.Request) {
+	if !cfg.authenticateAdmin(r) {
+		w.WriteHeader(401)
+		w.Write([]byte("API key incorrect"))
+		return
+	}
+
+	word := filter.NormalizeWord(r.PathValue("word"))
+	deleted, err := cfg.db.DeleteFilterWord(r.Context(), word)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+	if deleted == 0 {
+		w.WriteHeader(404)
+		w.Write([]byte("word not found"))
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerModerationFlags(w http.ResponseWriter, r *http.Request) {
+	if !cfg.authenticateAdmin(r) {
+		w.WriteHeader(401)
+		w.Write([]byte("API key incorrect"))
+		return
+	}
+
+	flags, err := cfg.db.GetUnresolvedModerationFlags(r.Context())
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	flagsToReturn := make([]ModerationFlag, len(flags))
+	for i, flag := range flags {
+		flagsToReturn[i] = ModerationFlag{
+			ID:        flag.ID,
+			CreatedAt: flag.CreatedAt,
+			ChirpID:   flag.ChirpID,
+			Words:     flag.Words,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	data, _ := json.Marshal(flagsToReturn)
+	w.Write(data)
+}
+
+func (cfg *apiConfig) handlerResolveModerationFlag(w http.ResponseWriter, r *http.Request) {
+	if !cfg.authenticateAdmin(r) {
+		w.WriteHeader(401)
+		w.Write([]byte("API key incorrect"))
+		return
+	}
+
+	flagID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("flag not found"))
+		return
+	}
+
+	resolved, err := cfg.db.ResolveModerationFlag(r.Context(), flagID)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+	if resolved == 0 {
+		w.WriteHeader(404)
+		w.Write([]byte("flag not found"))
+		return
+	}
+
+	w.WriteHeader(204)
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"servers/internal/database"
+	"servers/internal/pagination"
+
+	"github.com/google/uuid"
+)
+
+const (
+	notificationKindMention = "mention"
+)
+
+type Notification struct {
+	ID        uuid.UUID  `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	Kind      string     `json:"kind"`
+	ActorID   uuid.UUID  `json:"actor_id"`
+	ChirpID   *uuid.UUID `json:"chirp_id"`
+	Read      bool       `json:"read"`
+	ReadAt    *time.Time `json:"read_at"`
+}
+
+func notificationFromDB(notification database.Notification) Notification {
+	apiNotification := Notification{
+		ID:        notification.ID,
+		CreatedAt: notification.CreatedAt,
+		Kind:      notification.Kind,
+		ActorID:   notification.ActorID,
+		Read:      notification.ReadAt.Valid,
+	}
+	if notification.ChirpID.Valid {
+		chirpID := notification.ChirpID.UUID
+		apiNotification.ChirpID = &chirpID
+	}
+	if notification.ReadAt.Valid {
+		readAt := notification.ReadAt.Time
+		apiNotification.ReadAt = &readAt
+	}
+	return apiNotification
+}
+
+// handlerNotifications lists the caller's notifications with the same
+// pagination as GET /api/chirps. unread=true restricts it to unread ones.
+func (cfg *apiConfig) handlerNotifications(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	type notificationsResp struct {
+		Notifications []Notification `json:"notifications"`
+		UnreadCount   int64          `json:"unread_count"`
+		NextCursor    string         `json:"next_cursor,omitempty"`
+		PrevCursor    string         `json:"prev_cursor,omitempty"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	page, err := parsePageRequest(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+	unreadOnly := r.URL.Query().Get("unread") == "true"
+
+	cursorCreatedAt, cursorID := page.cursorArgs()
+	var notifications []database.Notification
+	if page.ascending() {
+		notifications, err = cfg.db.GetNotificationsAscending(r.Context(), database.GetNotificationsAscendingParams{
+			UserID:          userID,
+			UnreadOnly:      unreadOnly,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        page.rowLimit(),
+		})
+	} else {
+		notifications, err = cfg.db.GetNotificationsDescending(r.Context(), database.GetNotificationsDescendingParams{
+			UserID:          userID,
+			UnreadOnly:      unreadOnly,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        page.rowLimit(),
+		})
+	}
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	unreadCount, err := cfg.db.CountUnreadNotifications(r.Context(), userID)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	notifications, nextCursor, prevCursor := paginate(notifications, page, func(notification database.Notification) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: notification.CreatedAt, ID: notification.ID}
+	})
+	apiNotifications := make([]Notification, len(notifications))
+	for i, notification := range notifications {
+		apiNotifications[i] = notificationFromDB(notification)
+	}
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(notificationsResp{
+		Notifications: apiNotifications,
+		UnreadCount:   unreadCount,
+		NextCursor:    nextCursor,
+		PrevCursor:    prevCursor,
+	})
+	w.Write(data)
+}
+
+// handlerNotificationsRead marks the notifications in ids as read, or all of
+// the caller's notifications when ids is omitted.
+func (cfg *apiConfig) handlerNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		IDs []uuid.UUID `json:"ids"`
+	}
+
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	// an empty body is allowed and means "mark everything as read"
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&params)
+	if err != nil && err != io.EOF {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Invalid params"})
+		w.Write(data)
+		return
+	}
+
+	if params.IDs == nil {
+		err = cfg.db.MarkAllNotificationsRead(r.Context(), userID)
+	} else {
+		err = cfg.db.MarkNotificationsRead(r.Context(), database.MarkNotificationsReadParams{UserID: userID, Ids: params.IDs})
+	}
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	w.WriteHeader(204)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"servers/internal/database"
+)
+
+func TestResolveMentionsFallsBackToEmailLocalPart(t *testing.T) {
+	cfg := newTestConfig(t)
+	ctx := context.Background()
+	jane, err := cfg.db.CreateUser(ctx, database.CreateUserParams{
+		Email:          "jane.doe@example.com",
+		HashedPassword: "unused",
+		Handle:         "jane",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sam, err := cfg.db.CreateUser(ctx, database.CreateUserParams{
+		Email:          "sam@example.com",
+		HashedPassword: "unused",
+		Handle:         "samuel",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// someone else's handle wins over an email local part
+	other, err := cfg.db.CreateUser(ctx, database.CreateUserParams{
+		Email:          "jane@example.org",
+		HashedPassword: "unused",
+		Handle:         "someone_else",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userIDs, err := cfg.db.ResolveMentions(ctx, []string{"jane", "sam"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(userIDs) != 2 || !slices.Contains(userIDs, jane.ID) || !slices.Contains(userIDs, sam.ID) {
+		t.Errorf("got %v, want %v and %v", userIDs, jane.ID, sam.ID)
+	}
+	if slices.Contains(userIDs, other.ID) {
+		t.Errorf("email local part matched although the name is a handle")
+	}
+}
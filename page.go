@@ -0,0 +1,95 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"slices"
+
+	"servers/internal/pagination"
+
+	"github.com/google/uuid"
+)
+
+// pageRequest holds the keyset pagination parameters shared by every chirp
+// listing: limit, an optional before/after cursor and the sort direction.
+type pageRequest struct {
+	limit      int
+	cursor     *pagination.Cursor
+	before     bool
+	descending bool
+}
+
+func parsePageRequest(query url.Values) (pageRequest, error) {
+	page := pageRequest{descending: query.Get("sort") == "desc"}
+
+	limit, err := pagination.ParseLimit(query.Get("limit"))
+	if err != nil {
+		return page, err
+	}
+	page.limit = limit
+
+	after := query.Get("after")
+	before := query.Get("before")
+	if len(after) > 0 && len(before) > 0 {
+		return page, fmt.Errorf("only one of before and after can be set")
+	}
+
+	encoded := after
+	if len(before) > 0 {
+		encoded = before
+		page.before = true
+	}
+	if len(encoded) > 0 {
+		cursor, err := pagination.DecodeCursor(encoded)
+		if err != nil {
+			return page, err
+		}
+		page.cursor = &cursor
+	}
+	return page, nil
+}
+
+// ascending reports whether the rows should be read from the database in
+// ascending (created_at, id) order. Paging backwards runs the query in the
+// opposite direction of the listing and the rows are reversed afterwards.
+func (p pageRequest) ascending() bool {
+	return p.descending == p.before
+}
+
+func (p pageRequest) cursorArgs() (sql.NullTime, uuid.NullUUID) {
+	if p.cursor == nil {
+		return sql.NullTime{}, uuid.NullUUID{}
+	}
+	return sql.NullTime{Time: p.cursor.CreatedAt, Valid: true}, uuid.NullUUID{UUID: p.cursor.ID, Valid: true}
+}
+
+// rowLimit is one more than the page size so we can tell whether another
+// page exists without a separate count query.
+func (p pageRequest) rowLimit() int32 {
+	return int32(p.limit + 1)
+}
+
+// paginate trims rows fetched with rowLimit down to a page in listing order
+// and returns the cursors for the following and preceding pages.
+func paginate[T any, C interface{ Encode() string }](rows []T, page pageRequest, cursorOf func(T) C) ([]T, string, string) {
+	hasMore := len(rows) > page.limit
+	if hasMore {
+		rows = rows[:page.limit]
+	}
+	if page.before {
+		slices.Reverse(rows)
+	}
+	if len(rows) == 0 {
+		return rows, "", ""
+	}
+
+	nextCursor, prevCursor := "", ""
+	if hasMore || page.before {
+		nextCursor = cursorOf(rows[len(rows)-1]).Encode()
+	}
+	if (hasMore && page.before) || (page.cursor != nil && !page.before) {
+		prevCursor = cursorOf(rows[0]).Encode()
+	}
+	return rows, nextCursor, prevCursor
+}
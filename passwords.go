@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"servers/internal/auth"
+	"servers/internal/database"
+	"servers/internal/mail"
+)
+
+const (
+	passwordResetExpiry = time.Hour
+	// passwordResetCooldown is how long after one reset email another will
+	// not be sent, so the endpoint cannot be used to flood an inbox.
+	passwordResetCooldown = 5 * time.Minute
+)
+
+const passwordResetEmail = `Hi @%s,
+
+Someone asked to reset the password for your Chirpy account. To choose a
+new password, send the token below along with it to POST /api/password/reset:
+
+%s
+
+The token expires in an hour and can only be used once. If you did not ask
+for a reset you can ignore this email; your password has not changed.
+`
+
+// sendPasswordReset emails a reset token to the account registered with
+// email, if there is one and it has not been sent one in the last
+// passwordResetCooldown. Only a hash of the token is stored.
+func (cfg *apiConfig) sendPasswordReset(ctx context.Context, email string) error {
+	user, err := cfg.db.GetUserFromEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	token, err := auth.MakeRefreshToken()
+	if err != nil {
+		return err
+	}
+	created, err := cfg.db.CreatePasswordReset(ctx, database.CreatePasswordResetParams{
+		TokenHash:       auth.HashToken(token),
+		ExpiresAt:       time.Now().Add(passwordResetExpiry),
+		UserID:          user.ID,
+		CooldownSeconds: passwordResetCooldown.Seconds(),
+	})
+	if err != nil {
+		return err
+	}
+	if created == 0 {
+		return nil
+	}
+	return cfg.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body:    fmt.Sprintf(passwordResetEmail, user.Handle, token),
+	})
+}
+
+// purgePasswordResets removes reset tokens that can no longer be used.
+func (cfg *apiConfig) purgePasswordResets(ctx context.Context) {
+	_, err := cfg.db.DeleteExpiredPasswordResets(ctx)
+	if err != nil {
+		log.Printf("Error purging password resets: %v", err)
+	}
+}
+
+// handlerForgotPassword starts a password reset. It answers the same way
+// whether or not the email belongs to an account, and sends the email in the
+// background so that the response time does not give it away either.
+func (cfg *apiConfig) handlerForgotPassword(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Email string `json:"email"`
+	}
+
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&params)
+	if err != nil || len(strings.TrimSpace(params.Email)) == 0 {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Invalid params"})
+		w.Write(data)
+		return
+	}
+
+	go func() {
+		err := cfg.sendPasswordReset(context.Background(), params.Email)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Error sending password reset: %v", err)
+		}
+	}()
+
+	w.WriteHeader(202)
+}
+
+// handlerResetPassword sets a new password using a token from
+// handlerForgotPassword. Every session of the account is logged out.
+func (cfg *apiConfig) handlerResetPassword(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&params)
+	if err != nil || len(params.Token) == 0 {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Invalid params"})
+		w.Write(data)
+		return
+	}
+	if len(params.Password) == 0 {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Password cannot be empty"})
+		w.Write(data)
+		return
+	}
+
+	hashedPassword, err := auth.HashPassword(params.Password)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	tx, err := cfg.dbConn.BeginTx(r.Context(), nil)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	reset, err := qtx.UsePasswordReset(r.Context(), auth.HashToken(params.Token))
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Invalid or expired token"})
+		w.Write(data)
+		return
+	}
+	err = qtx.UpdatePassword(r.Context(), database.UpdatePasswordParams{
+		HashedPassword: hashedPassword,
+		ID:             reset.UserID,
+	})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	// outstanding reset emails and sessions could belong to whoever the
+	// password was reset to keep out
+	err = qtx.UseAllPasswordResets(r.Context(), reset.UserID)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	err = qtx.RevokeUserTokens(r.Context(), reset.UserID)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	w.WriteHeader(204)
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"servers/internal/database"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxPinnedChirps    = 3
+	maxPinnedChirpsRed = 10
+)
+
+var errTooManyPins = errors.New("too many pinned chirps")
+
+// pinLimitFor returns how many chirps the user can have pinned at once.
+func pinLimitFor(user database.User) int64 {
+	if user.IsChirpyRed {
+		return maxPinnedChirpsRed
+	}
+	return maxPinnedChirps
+}
+
+// pinChirp pins one of the user's chirps, returning errTooManyPins if that
+// would take them over their limit. The user's row is locked so concurrent
+// pins are counted one after the other.
+func (cfg *apiConfig) pinChirp(ctx context.Context, userID, chirpID uuid.UUID) error {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	user, err := qtx.GetUserForUpdate(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	added, err := qtx.PinChirp(ctx, database.PinChirpParams{UserID: userID, ChirpID: chirpID})
+	if err != nil {
+		return err
+	}
+	if added > 0 {
+		pins, err := qtx.CountPins(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if pins > pinLimitFor(user) {
+			return errTooManyPins
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (cfg *apiConfig) handlerPinChirp(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+	chirp, err := cfg.db.GetChirp(r.Context(), database.GetChirpParams{ID: chirpID, ViewerID: userID})
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+	// pinning a rechirp pins the original, which has to be the user's own
+	if chirp.RechirpOf.Valid {
+		chirp, err = cfg.db.GetChirp(r.Context(), database.GetChirpParams{ID: originalID(chirp), ViewerID: userID})
+		if err != nil {
+			w.WriteHeader(404)
+			w.Write([]byte("Chirp not found"))
+			return
+		}
+		chirpID = chirp.ID
+	}
+
+	// check if the user is the owner of the token
+	if chirp.UserID != userID {
+		w.WriteHeader(403)
+		w.Write([]byte("Unauthorized"))
+		return
+	}
+
+	err = cfg.pinChirp(r.Context(), userID, chirpID)
+	if errors.Is(err, errTooManyPins) {
+		user, err := cfg.db.GetUser(r.Context(), userID)
+		if err != nil {
+			log.Printf("%v", err)
+			w.WriteHeader(500)
+			w.Write([]byte("Server Error - something went wrong"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(409)
+		data, _ := json.Marshal(errorResp{
+			Error: fmt.Sprintf("You can pin at most %d chirps", pinLimitFor(user)),
+		})
+		w.Write(data)
+		return
+	}
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerUnpinChirp(w http.ResponseWriter, r *http.Request) {
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+	// undoing through a rechirp applies to the original, as pinning does
+	chirp, err := cfg.db.GetChirp(r.Context(), database.GetChirpParams{ID: chirpID, ViewerID: userID})
+	if err == nil {
+		chirpID = originalID(chirp)
+	}
+
+	err = cfg.db.UnpinChirp(r.Context(), database.UnpinChirpParams{UserID: userID, ChirpID: chirpID})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	w.WriteHeader(204)
+}
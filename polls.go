@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"servers/internal/database"
+
+	"github.com/google/uuid"
+)
+
+const (
+	minPollOptions      = 2
+	maxPollOptions      = 4
+	maxPollOptionLength = 25
+	maxPollDuration     = 7 * 24 * time.Hour
+)
+
+type Poll struct {
+	ID         uuid.UUID    `json:"id"`
+	ClosesAt   time.Time    `json:"closes_at"`
+	Closed     bool         `json:"closed"`
+	Options    []PollOption `json:"options"`
+	TotalVotes *int64       `json:"total_votes,omitempty"`
+	MyVote     *uuid.UUID   `json:"my_vote,omitempty"`
+}
+
+// PollOption is one answer of a poll. Votes is only set once the caller has
+// voted or the poll has closed, so that tallies do not sway voters.
+type PollOption struct {
+	ID    uuid.UUID `json:"id"`
+	Text  string    `json:"text"`
+	Votes *int64    `json:"votes,omitempty"`
+}
+
+type pollRequest struct {
+	Options         []string `json:"options"`
+	DurationSeconds int64    `json:"duration_seconds"`
+}
+
+// newPoll is a validated pollRequest.
+type newPoll struct {
+	options  []string
+	duration time.Duration
+}
+
+// validatePoll checks a poll sent along with a new chirp, trimming its
+// options of surrounding whitespace.
+func validatePoll(poll pollRequest) (*newPoll, error) {
+	if len(poll.Options) < minPollOptions || len(poll.Options) > maxPollOptions {
+		return nil, fmt.Errorf("A poll must have between %d and %d options", minPollOptions, maxPollOptions)
+	}
+	options := make([]string, len(poll.Options))
+	seen := map[string]bool{}
+	for i, option := range poll.Options {
+		option = strings.TrimSpace(option)
+		if len(option) == 0 {
+			return nil, errors.New("Poll options cannot be empty")
+		}
+		if utf8.RuneCountInString(option) > maxPollOptionLength {
+			return nil, fmt.Errorf("Poll options can be at most %d characters", maxPollOptionLength)
+		}
+		if seen[strings.ToLower(option)] {
+			return nil, errors.New("Poll options must be different")
+		}
+		seen[strings.ToLower(option)] = true
+		options[i] = option
+	}
+
+	maxSeconds := int64(maxPollDuration.Seconds())
+	if poll.DurationSeconds <= 0 || poll.DurationSeconds > maxSeconds {
+		return nil, fmt.Errorf("duration_seconds must be between 1 and %d", maxSeconds)
+	}
+	return &newPoll{options: options, duration: time.Duration(poll.DurationSeconds) * time.Second}, nil
+}
+
+// addPoll stores a poll for chirp inside the caller's transaction. The poll
+// closes its duration after the chirp is published, measured by the
+// database clock in UTC.
+func addPoll(ctx context.Context, qtx *database.Queries, chirp database.Chirp, poll *newPoll) error {
+	created, err := qtx.CreatePoll(ctx, database.CreatePollParams{
+		ChirpID:         chirp.ID,
+		OpensAt:         chirp.PublishAt,
+		DurationSeconds: poll.duration.Seconds(),
+	})
+	if err != nil {
+		return err
+	}
+	for i, option := range poll.options {
+		err = qtx.CreatePollOption(ctx, database.CreatePollOptionParams{
+			PollID:   created.ID,
+			Position: int32(i),
+			Text:     option,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pollsForChirps returns the polls of the given chirps keyed by chirp ID,
+// with tallies filled in where viewerID may see them.
+func (cfg *apiConfig) pollsForChirps(ctx context.Context, viewerID uuid.UUID, chirpIDs []uuid.UUID) (map[uuid.UUID]*Poll, error) {
+	rows, err := cfg.db.GetPollsForChirps(ctx, chirpIDs)
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+
+	polls := make(map[uuid.UUID]*Poll, len(rows))
+	byChirp := make(map[uuid.UUID]*Poll, len(rows))
+	pollIDs := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		poll := &Poll{
+			ID:       row.ID,
+			ClosesAt: row.ClosesAt,
+			Closed:   row.Closed,
+			Options:  []PollOption{},
+		}
+		polls[row.ID] = poll
+		byChirp[row.ChirpID] = poll
+		pollIDs[i] = row.ID
+	}
+
+	if viewerID != uuid.Nil {
+		votes, err := cfg.db.GetPollVotes(ctx, database.GetPollVotesParams{UserID: viewerID, PollIds: pollIDs})
+		if err != nil {
+			return nil, err
+		}
+		for _, vote := range votes {
+			optionID := vote.OptionID
+			polls[vote.PollID].MyVote = &optionID
+		}
+	}
+
+	options, err := cfg.db.GetPollOptions(ctx, pollIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, option := range options {
+		poll := polls[option.PollID]
+		apiOption := PollOption{ID: option.ID, Text: option.Text}
+		if poll.Closed || poll.MyVote != nil {
+			votes := option.Votes
+			apiOption.Votes = &votes
+			if poll.TotalVotes == nil {
+				poll.TotalVotes = new(int64)
+			}
+			*poll.TotalVotes += votes
+		}
+		poll.Options = append(poll.Options, apiOption)
+	}
+
+	return byChirp, nil
+}
+
+func (cfg *apiConfig) handlerVoteInPoll(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		OptionID uuid.UUID `json:"option_id"`
+	}
+
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+	chirp, err := cfg.db.GetChirp(r.Context(), database.GetChirpParams{ID: chirpID, ViewerID: userID})
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+	// votes through a rechirp go to the original's poll
+	chirpID = originalID(chirp)
+
+	w.Header().Set("Content-Type", "application/json")
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Invalid params"})
+		w.Write(data)
+		return
+	}
+
+	// the vote is only recorded while the poll is open by the database clock
+	voted, err := cfg.db.VoteInPoll(r.Context(), database.VoteInPollParams{
+		UserID:   userID,
+		ChirpID:  chirpID,
+		OptionID: params.OptionID,
+	})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	if voted == 0 {
+		poll, err := cfg.db.GetPoll(r.Context(), chirpID)
+		if errors.Is(err, sql.ErrNoRows) {
+			w.WriteHeader(404)
+			data, _ := json.Marshal(errorResp{Error: "Chirp has no poll"})
+			w.Write(data)
+			return
+		}
+		if err != nil {
+			log.Printf("%v", err)
+			w.WriteHeader(500)
+			data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+			w.Write(data)
+			return
+		}
+		if poll.Closed {
+			w.WriteHeader(409)
+			data, _ := json.Marshal(errorResp{Error: "Poll is closed"})
+			w.Write(data)
+			return
+		}
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Invalid poll option"})
+		w.Write(data)
+		return
+	}
+
+	chirpsToReturn, err := cfg.hydrateChirps(r.Context(), userID, []database.Chirp{chirp})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(chirpsToReturn[0])
+	w.Write(data)
+}
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"servers/internal/auth"
+	"servers/internal/database"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	minHandleLength      = 3
+	maxHandleLength      = 30
+	maxDisplayNameLength = 50
+	maxBioLength         = 160
+	maxLocationLength    = 30
+)
+
+var (
+	handlePattern   = regexp.MustCompile(`^[a-z0-9_]+$`)
+	handleForbidden = regexp.MustCompile(`[^a-z0-9_]`)
+)
+
+// Profile is the public face of an account and is safe to show to anyone.
+// Unlike User it never includes the email address.
+type Profile struct {
+	ID          uuid.UUID   `json:"id"`
+	CreatedAt   time.Time   `json:"created_at"`
+	Handle      string      `json:"handle"`
+	DisplayName string      `json:"display_name"`
+	Bio         string      `json:"bio"`
+	Location    string      `json:"location"`
+	Avatar      *Attachment `json:"avatar"`
+	IsChirpyRed bool        `json:"is_chirpy_red"`
+}
+
+// isUniqueViolation reports whether err is Postgres refusing a duplicate
+// value, such as an email or handle taken between checking for it and
+// saving it.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// isForeignKeyViolation reports whether err is Postgres refusing a reference
+// to a row that does not exist, such as one deleted after it was looked up.
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
+
+// validateHandle lowercases a handle and checks that it is 3 to 30 letters,
+// digits or underscores.
+func validateHandle(handle string) (string, error) {
+	handle = strings.ToLower(strings.TrimSpace(handle))
+	if len(handle) < minHandleLength || len(handle) > maxHandleLength || !handlePattern.MatchString(handle) {
+		return "", fmt.Errorf("Handle must be %d to %d letters, digits or underscores", minHandleLength, maxHandleLength)
+	}
+	return handle, nil
+}
+
+// validateProfileText trims a free-text profile field and checks its length.
+func validateProfileText(field, value string, maxLength int) (string, error) {
+	value = strings.TrimSpace(value)
+	if utf8.RuneCountInString(value) > maxLength {
+		return "", fmt.Errorf("%s can be at most %d characters", field, maxLength)
+	}
+	return value, nil
+}
+
+// defaultHandle picks a handle for a new account that did not ask for one:
+// the local part of its email address where that makes a valid handle that
+// is not taken, with a random suffix otherwise.
+func (cfg *apiConfig) defaultHandle(ctx context.Context, email string) (string, error) {
+	base := handleForbidden.ReplaceAllString(strings.ToLower(strings.Split(email, "@")[0]), "_")
+	if len(base) > maxHandleLength {
+		base = base[:maxHandleLength]
+	}
+	if len(base) >= minHandleLength {
+		_, err := cfg.db.GetUserByHandle(ctx, base)
+		if errors.Is(err, sql.ErrNoRows) {
+			return base, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	suffix := make([]byte, 3)
+	_, err := rand.Read(suffix)
+	if err != nil {
+		return "", err
+	}
+	if len(base) > maxHandleLength-7 {
+		base = base[:maxHandleLength-7]
+	}
+	return base + "_" + hex.EncodeToString(suffix), nil
+}
+
+// avatarFor returns the user's avatar, or nil if they have not set one.
+func (cfg *apiConfig) avatarFor(ctx context.Context, user database.User) (*Attachment, error) {
+	if !user.AvatarID.Valid {
+		return nil, nil
+	}
+	attachment, err := cfg.db.GetAttachment(ctx, user.AvatarID.UUID)
+	if err != nil {
+		return nil, err
+	}
+	avatar := attachmentFromDB(attachment)
+	return &avatar, nil
+}
+
+func (cfg *apiConfig) profileFromDB(ctx context.Context, user database.User) (Profile, error) {
+	avatar, err := cfg.avatarFor(ctx, user)
+	if err != nil {
+		return Profile{}, err
+	}
+	return Profile{
+		ID:          user.ID,
+		CreatedAt:   user.CreatedAt,
+		Handle:      user.Handle,
+		DisplayName: user.DisplayName,
+		Bio:         user.Bio,
+		Location:    user.Location,
+		Avatar:      avatar,
+		IsChirpyRed: user.IsChirpyRed,
+	}, nil
+}
+
+// userFromDB converts a user into the private view of their account, which
+// is only ever returned to the user themselves.
+func (cfg *apiConfig) userFromDB(ctx context.Context, user database.User) (User, error) {
+	avatar, err := cfg.avatarFor(ctx, user)
+	if err != nil {
+		return User{}, err
+	}
+	return User{
+		ID:               user.ID,
+		CreatedAt:        user.CreatedAt,
+		UpdatedAt:        user.UpdatedAt,
+		Email:            user.Email,
+		EmailVerified:    user.EmailVerified,
+		TwoFactorEnabled: user.TotpEnabled,
+		IsChirpyRed:      user.IsChirpyRed,
+		Handle:           user.Handle,
+		DisplayName:      user.DisplayName,
+		Bio:              user.Bio,
+		Location:         user.Location,
+		Avatar:           avatar,
+	}, nil
+}
+
+func (cfg *apiConfig) handlerProfile(w http.ResponseWriter, r *http.Request) {
+	user, err := cfg.db.GetUserByHandle(r.Context(), strings.ToLower(r.PathValue("handle")))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("user not found"))
+		return
+	}
+
+	profile, err := cfg.profileFromDB(r.Context(), user)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	data, _ := json.Marshal(profile)
+	w.Write(data)
+}
+
+// handlerUpdateUser changes any of the caller's account and profile fields.
+// Fields left out of the request keep their current value, so the profile can
+// be edited without sending a new password.
+func (cfg *apiConfig) handlerUpdateUser(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Email       *string    `json:"email"`
+		Password    *string    `json:"password"`
+		Handle      *string    `json:"handle"`
+		DisplayName *string    `json:"display_name"`
+		Bio         *string    `json:"bio"`
+		Location    *string    `json:"location"`
+		AvatarID    *uuid.UUID `json:"avatar_id"`
+	}
+
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Invalid params"})
+		w.Write(data)
+		return
+	}
+
+	user, err := cfg.db.GetUser(r.Context(), userID)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	update := database.UpdateUserParams{
+		Email:          user.Email,
+		HashedPassword: user.HashedPassword,
+		Handle:         user.Handle,
+		DisplayName:    user.DisplayName,
+		Bio:            user.Bio,
+		Location:       user.Location,
+		AvatarID:       user.AvatarID,
+		EmailVerified:  user.EmailVerified,
+		ID:             user.ID,
+	}
+
+	if params.Email != nil {
+		if len(strings.TrimSpace(*params.Email)) == 0 {
+			w.WriteHeader(400)
+			data, _ := json.Marshal(errorResp{Error: "Email cannot be empty"})
+			w.Write(data)
+			return
+		}
+		// a new address has to be verified again
+		if *params.Email != user.Email {
+			update.Email = *params.Email
+			update.EmailVerified = false
+		}
+	}
+	if params.Password != nil {
+		update.HashedPassword, err = auth.HashPassword(*params.Password)
+		if err != nil {
+			log.Printf("%v", err)
+			w.WriteHeader(500)
+			data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+			w.Write(data)
+			return
+		}
+	}
+	if params.Handle != nil {
+		update.Handle, err = validateHandle(*params.Handle)
+		if err != nil {
+			w.WriteHeader(400)
+			data, _ := json.Marshal(errorResp{Error: err.Error()})
+			w.Write(data)
+			return
+		}
+		owner, err := cfg.db.GetUserByHandle(r.Context(), update.Handle)
+		if err == nil && owner.ID != userID {
+			w.WriteHeader(409)
+			data, _ := json.Marshal(errorResp{Error: "Handle already taken"})
+			w.Write(data)
+			return
+		}
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("%v", err)
+			w.WriteHeader(500)
+			data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+			w.Write(data)
+			return
+		}
+	}
+	if params.DisplayName != nil {
+		update.DisplayName, err = validateProfileText("Display name", *params.DisplayName, maxDisplayNameLength)
+		if err != nil {
+			w.WriteHeader(400)
+			data, _ := json.Marshal(errorResp{Error: err.Error()})
+			w.Write(data)
+			return
+		}
+	}
+	if params.Bio != nil {
+		update.Bio, err = validateProfileText("Bio", *params.Bio, maxBioLength)
+		if err != nil {
+			w.WriteHeader(400)
+			data, _ := json.Marshal(errorResp{Error: err.Error()})
+			w.Write(data)
+			return
+		}
+	}
+	if params.Location != nil {
+		update.Location, err = validateProfileText("Location", *params.Location, maxLocationLength)
+		if err != nil {
+			w.WriteHeader(400)
+			data, _ := json.Marshal(errorResp{Error: err.Error()})
+			w.Write(data)
+			return
+		}
+	}
+	if params.AvatarID != nil {
+		attachment, err := cfg.db.GetAttachment(r.Context(), *params.AvatarID)
+		if err != nil || attachment.UserID != userID {
+			w.WriteHeader(400)
+			data, _ := json.Marshal(errorResp{Error: "Avatar not found"})
+			w.Write(data)
+			return
+		}
+		update.AvatarID = uuid.NullUUID{UUID: attachment.ID, Valid: true}
+	}
+
+	emailChanged := update.Email != user.Email
+	user, err = cfg.db.UpdateUser(r.Context(), update)
+	if isUniqueViolation(err) {
+		w.WriteHeader(409)
+		data, _ := json.Marshal(errorResp{Error: "Email or handle already taken"})
+		w.Write(data)
+		return
+	}
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	if emailChanged {
+		err = cfg.sendVerification(r.Context(), user)
+		if err != nil {
+			log.Printf("Error sending verification email: %v", err)
+		}
+	}
+
+	userToReturn, err := cfg.userFromDB(r.Context(), user)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(userToReturn)
+	w.Write(data)
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"servers/internal/database"
+
+	"github.com/google/uuid"
+)
+
+// ChirpRef is a chirp embedded in a rechirp or quote. When the referenced
+// chirp has been deleted, or the viewer is not allowed to see it, it is a
+// tombstone: Deleted is set and only the ID is kept.
+type ChirpRef struct {
+	ID      uuid.UUID `json:"id"`
+	Deleted bool      `json:"deleted"`
+	Chirp   *Chirp    `json:"chirp,omitempty"`
+}
+
+// originalID returns the chirp a rechirp points to, so that replies, quotes
+// and rechirps always refer to the original rather than to a repost of it.
+func originalID(chirp database.Chirp) uuid.UUID {
+	if chirp.RechirpOf.Valid {
+		return chirp.RechirpOf.UUID
+	}
+	return chirp.ID
+}
+
+// embedReferencedChirps fills in RechirpOf and QuoteOf of apiChirps, which
+// were converted from chirps. Embedded chirps are hydrated but do not embed
+// the chirps they refer to themselves.
+func (cfg *apiConfig) embedReferencedChirps(ctx context.Context, viewerID uuid.UUID, chirps []database.Chirp, apiChirps []Chirp) error {
+	refIDs := []uuid.UUID{}
+	for _, chirp := range chirps {
+		if chirp.RechirpOf.Valid {
+			refIDs = append(refIDs, chirp.RechirpOf.UUID)
+		}
+		if chirp.QuoteOf.Valid {
+			refIDs = append(refIDs, chirp.QuoteOf.UUID)
+		}
+	}
+	if len(refIDs) == 0 {
+		return nil
+	}
+
+	referenced, err := cfg.db.GetChirpsByIDs(ctx, database.GetChirpsByIDsParams{Ids: refIDs, ViewerID: viewerID})
+	if err != nil {
+		return err
+	}
+	apiReferenced, err := cfg.fillChirps(ctx, viewerID, referenced)
+	if err != nil {
+		return err
+	}
+	byID := make(map[uuid.UUID]*Chirp, len(apiReferenced))
+	for i := range apiReferenced {
+		byID[apiReferenced[i].ID] = &apiReferenced[i]
+	}
+
+	ref := func(id uuid.NullUUID) *ChirpRef {
+		if !id.Valid {
+			return nil
+		}
+		chirp, ok := byID[id.UUID]
+		return &ChirpRef{ID: id.UUID, Deleted: !ok, Chirp: chirp}
+	}
+	for i, chirp := range chirps {
+		apiChirps[i].RechirpOf = ref(chirp.RechirpOf)
+		apiChirps[i].QuoteOf = ref(chirp.QuoteOf)
+	}
+	return nil
+}
+
+func (cfg *apiConfig) handlerRechirp(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+	original, err := cfg.db.GetChirp(r.Context(), database.GetChirpParams{ID: chirpID, ViewerID: userID})
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+	if original.RechirpOf.Valid {
+		original, err = cfg.db.GetChirp(r.Context(), database.GetChirpParams{ID: original.RechirpOf.UUID, ViewerID: userID})
+		if err != nil {
+			w.WriteHeader(404)
+			w.Write([]byte("Chirp not found"))
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	user, err := cfg.db.GetUser(r.Context(), userID)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	if !user.EmailVerified {
+		w.WriteHeader(403)
+		data, _ := json.Marshal(errorResp{Error: "Verify your email address before posting chirps"})
+		w.Write(data)
+		return
+	}
+	if original.UserID == userID {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "You cannot rechirp your own chirp"})
+		w.Write(data)
+		return
+	}
+	if original.Visibility != visibilityPublic {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Only public chirps can be rechirped"})
+		w.Write(data)
+		return
+	}
+
+	rechirp, err := cfg.db.CreateRechirp(r.Context(), database.CreateRechirpParams{
+		UserID:    userID,
+		RechirpOf: uuid.NullUUID{UUID: original.ID, Valid: true},
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		w.WriteHeader(409)
+		data, _ := json.Marshal(errorResp{Error: "Chirp already rechirped"})
+		w.Write(data)
+		return
+	}
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	chirpsToReturn, err := cfg.hydrateChirps(r.Context(), userID, []database.Chirp{rechirp})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	w.WriteHeader(201)
+	data, _ := json.Marshal(chirpsToReturn[0])
+	w.Write(data)
+}
+
+func (cfg *apiConfig) handlerUndoRechirp(w http.ResponseWriter, r *http.Request) {
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+
+	removed, err := cfg.db.DeleteRechirp(r.Context(), database.DeleteRechirpParams{
+		UserID:    userID,
+		RechirpOf: uuid.NullUUID{UUID: chirpID, Valid: true},
+	})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+	if removed == 0 {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+
+	w.WriteHeader(204)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"servers/internal/database"
+	"servers/internal/pagination"
+)
+
+const (
+	schedulePublishInterval = 15 * time.Second
+	schedulePublishBatch    = 100
+)
+
+// runPublisher periodically publishes scheduled chirps that are due. All
+// state lives in the database, so chirps that came due while the server was
+// down are published on the next tick after a restart. It runs until ctx is
+// cancelled.
+func (cfg *apiConfig) runPublisher(ctx context.Context) {
+	ticker := time.NewTicker(schedulePublishInterval)
+	defer ticker.Stop()
+	for {
+		published, err := cfg.publishDueChirps(ctx)
+		if err != nil {
+			log.Printf("Error publishing scheduled chirps: %v", err)
+		} else if published > 0 {
+			log.Printf("Published %d scheduled chirps", published)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishDueChirps publishes due chirps in batches until none are left.
+func (cfg *apiConfig) publishDueChirps(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		published, err := cfg.publishBatch(ctx)
+		total += published
+		if err != nil || published < schedulePublishBatch {
+			return total, err
+		}
+	}
+}
+
+// publishBatch publishes up to schedulePublishBatch due chirps and adds their
+// hashtags and mentions in a single transaction. The rows are claimed with
+// FOR UPDATE SKIP LOCKED, so several server instances can run the publisher
+// against the same database without publishing a chirp twice.
+func (cfg *apiConfig) publishBatch(ctx context.Context) (int, error) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	chirps, err := qtx.PublishDueChirps(ctx, schedulePublishBatch)
+	if err != nil {
+		return 0, err
+	}
+	for _, chirp := range chirps {
+		err = addTags(ctx, qtx, chirp)
+		if err != nil {
+			return 0, err
+		}
+		err = addMentions(ctx, qtx, chirp)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(chirps), tx.Commit()
+}
+
+func (cfg *apiConfig) handlerScheduledChirps(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	page, err := parsePageRequest(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+
+	cursorPublishAt, cursorID := page.cursorArgs()
+	var chirps []database.Chirp
+	if page.ascending() {
+		chirps, err = cfg.db.GetScheduledChirpsAscending(r.Context(), database.GetScheduledChirpsAscendingParams{
+			UserID:          userID,
+			CursorPublishAt: cursorPublishAt,
+			CursorID:        cursorID,
+			RowLimit:        page.rowLimit(),
+		})
+	} else {
+		chirps, err = cfg.db.GetScheduledChirpsDescending(r.Context(), database.GetScheduledChirpsDescendingParams{
+			UserID:          userID,
+			CursorPublishAt: cursorPublishAt,
+			CursorID:        cursorID,
+			RowLimit:        page.rowLimit(),
+		})
+	}
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	chirps, nextCursor, prevCursor := paginate(chirps, page, func(chirp database.Chirp) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: chirp.PublishAt.Time, ID: chirp.ID}
+	})
+	apiChirps, err := cfg.hydrateChirps(r.Context(), userID, chirps)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(chirpPageResp{
+		Chirps:     apiChirps,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	})
+	w.Write(data)
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"html"
+	"log"
+	"net/http"
+	"strings"
+
+	"servers/internal/database"
+	"servers/internal/pagination"
+
+	"github.com/google/uuid"
+)
+
+// Search highlights come back from the database with matches between these
+// private use characters rather than tags, since chirp bodies are not HTML.
+const (
+	highlightStart = "\ue000"
+	highlightStop  = "\ue001"
+)
+
+// highlightHTML escapes a search highlight and marks its matches with <mark>.
+func highlightHTML(highlight string) string {
+	highlight = html.EscapeString(highlight)
+	highlight = strings.ReplaceAll(highlight, highlightStart, "<mark>")
+	return strings.ReplaceAll(highlight, highlightStop, "</mark>")
+}
+
+// handlerSearchChirps runs a full-text search over chirp bodies. q accepts
+// web search syntax, so "quoted phrases", OR and -negation all work. Results
+// are ordered by relevance and can only be paged forward with after.
+func (cfg *apiConfig) handlerSearchChirps(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	type searchResult struct {
+		Chirp
+		Rank      float32 `json:"rank"`
+		Highlight string  `json:"highlight"`
+	}
+
+	type searchResp struct {
+		Chirps     []searchResult `json:"chirps"`
+		NextCursor string         `json:"next_cursor,omitempty"`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if len(query) == 0 {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "q is required"})
+		w.Write(data)
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+	page := pageRequest{limit: limit}
+	viewerID := cfg.viewer(r)
+
+	params := database.SearchChirpsParams{
+		Query:    query,
+		ViewerID: viewerID,
+		AuthorID: uuid.Nil,
+		RowLimit: page.rowLimit(),
+	}
+	if after := r.URL.Query().Get("after"); len(after) > 0 {
+		cursor, err := pagination.DecodeRankedCursor(after)
+		if err != nil {
+			w.WriteHeader(400)
+			data, _ := json.Marshal(errorResp{Error: err.Error()})
+			w.Write(data)
+			return
+		}
+		params.CursorRank = sql.NullFloat64{Float64: float64(cursor.Rank), Valid: true}
+		params.CursorCreatedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+	if authorIDFromQuery := r.URL.Query().Get("author_id"); len(authorIDFromQuery) > 0 {
+		params.AuthorID, err = uuid.Parse(authorIDFromQuery)
+		if err != nil {
+			w.WriteHeader(400)
+			data, _ := json.Marshal(errorResp{Error: "invalid author_id"})
+			w.Write(data)
+			return
+		}
+	}
+
+	rows, err := cfg.db.SearchChirps(r.Context(), params)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	rows, nextCursor, _ := paginate(rows, page, func(row database.SearchChirpsRow) pagination.RankedCursor {
+		return pagination.RankedCursor{
+			Rank:   row.Rank,
+			Cursor: pagination.Cursor{CreatedAt: row.CreatedAt, ID: row.ID},
+		}
+	})
+
+	chirps := make([]database.Chirp, len(rows))
+	for i, row := range rows {
+		chirps[i] = database.Chirp{
+			ID:        row.ID,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+			Body:      row.Body,
+			UserID:    row.UserID,
+			ParentID:  row.ParentID,
+			QuoteOf:   row.QuoteOf,
+
+			ContentWarning: row.ContentWarning,
+			Sensitive:      row.Sensitive,
+			Visibility:     row.Visibility,
+		}
+	}
+	apiChirps, err := cfg.hydrateChirps(r.Context(), viewerID, chirps)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	cfg.recordViews(viewerID, apiChirps)
+
+	results := make([]searchResult, len(rows))
+	for i, row := range rows {
+		results[i] = searchResult{
+			Chirp:     apiChirps[i],
+			Rank:      row.Rank,
+			Highlight: highlightHTML(row.Highlight),
+		}
+	}
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(searchResp{
+		Chirps:     results,
+		NextCursor: nextCursor,
+	})
+	w.Write(data)
+}
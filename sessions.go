@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"servers/internal/database"
+
+	"github.com/google/uuid"
+)
+
+const maxUserAgentLength = 512
+
+// Session is a refresh token as shown to its owner. The token itself is
+// never included; sessions are referred to by ID instead.
+type Session struct {
+	ID         uuid.UUID `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+}
+
+func sessionFromDB(token database.RefreshToken) Session {
+	return Session{
+		ID:         token.ID,
+		CreatedAt:  token.CreatedAt,
+		LastUsedAt: token.LastUsedAt,
+		ExpiresAt:  token.ExpiresAt,
+		UserAgent:  token.UserAgent,
+		IPAddress:  token.IpAddress,
+	}
+}
+
+// clientIP returns the address the request came from.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// userAgent returns the request's User-Agent, cut short if it is unreasonably
+// long.
+func userAgent(r *http.Request) string {
+	agent := r.UserAgent()
+	if len(agent) > maxUserAgentLength {
+		agent = agent[:maxUserAgentLength]
+	}
+	return agent
+}
+
+// writeSessions responds with userID's active sessions, most recently used
+// first.
+func (cfg *apiConfig) writeSessions(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	tokens, err := cfg.db.GetSessions(r.Context(), userID)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	sessions := make([]Session, len(tokens))
+	for i, token := range tokens {
+		sessions[i] = sessionFromDB(token)
+	}
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(sessions)
+	w.Write(data)
+}
+
+// revokeAllSessions logs userID out everywhere. Access tokens already issued
+// stay valid until they expire.
+func (cfg *apiConfig) revokeAllSessions(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	err := cfg.db.RevokeUserTokens(r.Context(), userID)
+	if err != nil {
+		log.Printf("%v", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerSessions(w http.ResponseWriter, r *http.Request) {
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	cfg.writeSessions(w, r, userID)
+}
+
+func (cfg *apiConfig) handlerRevokeSession(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	sessionID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Session not found"))
+		return
+	}
+
+	revoked, err := cfg.db.RevokeSession(r.Context(), database.RevokeSessionParams{ID: sessionID, UserID: userID})
+	if err != nil {
+		log.Printf("%v", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	if revoked == 0 {
+		w.WriteHeader(404)
+		w.Write([]byte("Session not found"))
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerRevokeAllSessions(w http.ResponseWriter, r *http.Request) {
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	cfg.revokeAllSessions(w, r, userID)
+}
+
+// handlerAdminSessions lets support see a user's sessions.
+func (cfg *apiConfig) handlerAdminSessions(w http.ResponseWriter, r *http.Request) {
+	if !cfg.authenticateAdmin(r) {
+		w.WriteHeader(401)
+		w.Write([]byte("API key incorrect"))
+		return
+	}
+
+	userID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("user not found"))
+		return
+	}
+
+	cfg.writeSessions(w, r, userID)
+}
+
+// handlerAdminRevokeAllSessions lets support log a user out everywhere, for
+// example when their account has been taken over.
+func (cfg *apiConfig) handlerAdminRevokeAllSessions(w http.ResponseWriter, r *http.Request) {
+	if !cfg.authenticateAdmin(r) {
+		w.WriteHeader(401)
+		w.Write([]byte("API key incorrect"))
+		return
+	}
+
+	userID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("user not found"))
+		return
+	}
+
+	cfg.revokeAllSessions(w, r, userID)
+}
@@ -0,0 +1,49 @@
+-- name: CreateAttachment :one
+INSERT INTO attachments (id, created_at, user_id, chirp_id, position, content_type, size_bytes, width, height, storage_key, thumbnail_key)
+VALUES (sqlc.arg(id), NOW(), sqlc.arg(user_id), NULL, 0, sqlc.arg(content_type), sqlc.arg(size_bytes), sqlc.arg(width), sqlc.arg(height), sqlc.arg(storage_key), sqlc.arg(thumbnail_key))
+RETURNING *;
+
+-- name: AttachMedia :execrows
+UPDATE attachments SET chirp_id = sqlc.arg(chirp_id), position = array_position(sqlc.arg(ids)::uuid[], id)
+WHERE id = ANY(sqlc.arg(ids)::uuid[]) AND user_id = sqlc.arg(user_id) AND chirp_id IS NULL;
+
+-- name: GetAttachmentsForChirps :many
+SELECT * FROM attachments
+WHERE chirp_id = ANY(sqlc.arg(ids)::uuid[])
+ORDER BY chirp_id, position;
+
+-- name: GetAttachment :one
+SELECT * FROM attachments WHERE id = $1;
+
+-- name: DeleteUnattachedMedia :many
+DELETE FROM attachments
+WHERE chirp_id IS NULL AND created_at < NOW() - make_interval(secs => sqlc.arg(max_age_seconds)::float8)
+AND NOT EXISTS (SELECT 1 FROM users WHERE users.avatar_id = attachments.id)
+RETURNING storage_key, thumbnail_key;
+
+-- name: DeletePurgedChirpMedia :many
+DELETE FROM attachments
+WHERE chirp_id IN (
+  SELECT id FROM chirps WHERE deleted_at < NOW() - make_interval(secs => sqlc.arg(retention_seconds)::float8)
+)
+RETURNING storage_key, thumbnail_key;
+
+-- name: GetMediaAccess :one
+-- Media in a chirp is as visible as the chirp, and gone once the chirp is
+-- deleted. Avatars are public, and
+-- uploads that are not part of anything yet are only visible to the uploader.
+SELECT
+  CASE WHEN chirps.id IS NOT NULL
+    THEN chirps.deleted_at IS NULL
+      AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, sqlc.arg(viewer_id))
+      AND (chirps.publish_at IS NULL OR chirps.user_id = sqlc.arg(viewer_id))
+    ELSE attachments.user_id = sqlc.arg(viewer_id)
+      OR EXISTS (SELECT 1 FROM users WHERE users.avatar_id = attachments.id)
+  END::boolean AS visible,
+  CASE WHEN chirps.id IS NOT NULL
+    THEN chirps.visibility = 'public' AND chirps.publish_at IS NULL
+    ELSE EXISTS (SELECT 1 FROM users WHERE users.avatar_id = attachments.id)
+  END::boolean AS public
+FROM attachments
+LEFT JOIN chirps ON chirps.id = attachments.chirp_id
+WHERE attachments.storage_key = sqlc.arg(key) OR attachments.thumbnail_key = sqlc.arg(key);
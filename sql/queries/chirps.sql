@@ -1,20 +1,237 @@
 -- name: CreateChirp :one
-INSERT INTO chirps (id, created_at, updated_at, body, user_id)
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, parent_id, publish_at, quote_of, content_warning, sensitive, visibility)
 VALUES (
 gen_random_uuid(),
 NOW(),
 NOW(),
-$1,
-$2
+sqlc.arg(body),
+sqlc.arg(user_id),
+sqlc.narg(parent_id),
+sqlc.narg(publish_at),
+sqlc.narg(quote_of),
+sqlc.narg(content_warning),
+sqlc.arg(sensitive),
+sqlc.arg(visibility)
 )
 RETURNING *;
 
--- name: GetChirps :many
-SELECT * FROM chirps 
-WHERE ($1 = CAST('00000000-0000-0000-0000-000000000000' as uuid) OR user_id = $1);
+-- name: CreateRechirp :one
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, rechirp_of)
+VALUES (gen_random_uuid(), NOW(), NOW(), '', sqlc.arg(user_id), sqlc.arg(rechirp_of))
+ON CONFLICT (user_id, rechirp_of) WHERE rechirp_of IS NOT NULL AND deleted_at IS NULL DO NOTHING
+RETURNING *;
+
+-- name: DeleteRechirp :execrows
+UPDATE chirps SET deleted_at = NOW() WHERE user_id = $1 AND rechirp_of = $2 AND deleted_at IS NULL;
+
+-- name: GetRechirpCounts :many
+SELECT rechirp_of, COUNT(*) AS rechirp_count FROM chirps
+WHERE rechirp_of = ANY(sqlc.arg(ids)::uuid[]) AND deleted_at IS NULL
+GROUP BY rechirp_of;
+
+-- name: GetChirpsByIDs :many
+SELECT * FROM chirps
+WHERE id = ANY(sqlc.arg(ids)::uuid[]) AND deleted_at IS NULL AND publish_at IS NULL
+AND chirp_visible_to(id, user_id, visibility, sqlc.arg(viewer_id)::uuid);
+
+-- name: GetChirpsAscending :many
+SELECT chirps.* FROM chirps
+LEFT JOIN chirps original ON original.id = chirps.rechirp_of
+WHERE chirps.deleted_at IS NULL AND chirps.publish_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, sqlc.arg(viewer_id)::uuid)
+AND (chirps.rechirp_of IS NULL OR original.deleted_at IS NULL)
+AND (NOT sqlc.arg(hide_sensitive)::boolean OR (NOT COALESCE(original.sensitive, chirps.sensitive) AND COALESCE(original.content_warning, chirps.content_warning) IS NULL))
+AND (sqlc.arg(author_id)::uuid = CAST('00000000-0000-0000-0000-000000000000' as uuid) OR (chirps.user_id = sqlc.arg(author_id)::uuid AND NOT EXISTS (SELECT 1 FROM pins WHERE pins.chirp_id = chirps.id)))
+AND (sqlc.narg(cursor_created_at)::timestamp IS NULL OR (chirps.created_at, chirps.id) > (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY chirps.created_at ASC, chirps.id ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetChirpsDescending :many
+SELECT chirps.* FROM chirps
+LEFT JOIN chirps original ON original.id = chirps.rechirp_of
+WHERE chirps.deleted_at IS NULL AND chirps.publish_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, sqlc.arg(viewer_id)::uuid)
+AND (chirps.rechirp_of IS NULL OR original.deleted_at IS NULL)
+AND (NOT sqlc.arg(hide_sensitive)::boolean OR (NOT COALESCE(original.sensitive, chirps.sensitive) AND COALESCE(original.content_warning, chirps.content_warning) IS NULL))
+AND (sqlc.arg(author_id)::uuid = CAST('00000000-0000-0000-0000-000000000000' as uuid) OR (chirps.user_id = sqlc.arg(author_id)::uuid AND NOT EXISTS (SELECT 1 FROM pins WHERE pins.chirp_id = chirps.id)))
+AND (sqlc.narg(cursor_created_at)::timestamp IS NULL OR (chirps.created_at, chirps.id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT sqlc.arg(row_limit);
 
 -- name: GetChirp :one
+SELECT * FROM chirps
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL AND publish_at IS NULL
+AND chirp_visible_to(id, user_id, visibility, sqlc.arg(viewer_id)::uuid);
+
+-- name: GetChirpIncludingScheduled :one
+SELECT * FROM chirps WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetChirpIncludingDeleted :one
 SELECT * FROM chirps WHERE id = $1;
 
 -- name: DeleteChirp :exec
-DELETE FROM chirps WHERE id = $1;
+UPDATE chirps SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreChirp :one
+UPDATE chirps SET deleted_at = NULL
+WHERE id = sqlc.arg(id)
+AND deleted_at > NOW() - make_interval(secs => sqlc.arg(retention_seconds)::float8)
+RETURNING *;
+
+-- name: PurgeDeletedChirps :execrows
+DELETE FROM chirps WHERE deleted_at < NOW() - make_interval(secs => sqlc.arg(retention_seconds)::float8);
+
+-- name: GetTrashAscending :many
+SELECT * FROM chirps
+WHERE user_id = sqlc.arg(user_id)
+AND deleted_at > NOW() - make_interval(secs => sqlc.arg(retention_seconds)::float8)
+AND (sqlc.narg(cursor_deleted_at)::timestamp IS NULL OR (deleted_at, id) > (sqlc.narg(cursor_deleted_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY deleted_at ASC, id ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetTrashDescending :many
+SELECT * FROM chirps
+WHERE user_id = sqlc.arg(user_id)
+AND deleted_at > NOW() - make_interval(secs => sqlc.arg(retention_seconds)::float8)
+AND (sqlc.narg(cursor_deleted_at)::timestamp IS NULL OR (deleted_at, id) < (sqlc.narg(cursor_deleted_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY deleted_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetChirpAncestors :many
+WITH RECURSIVE ancestors AS (
+  SELECT id, created_at, updated_at, body, user_id, parent_id, search_vector, deleted_at, publish_at, rechirp_of, quote_of, content_warning, sensitive, visibility, 1 AS depth
+  FROM chirps
+  WHERE id = (SELECT parent_id FROM chirps WHERE chirps.id = sqlc.arg(id))
+  UNION ALL
+  SELECT c.id, c.created_at, c.updated_at, c.body, c.user_id, c.parent_id, c.search_vector, c.deleted_at, c.publish_at, c.rechirp_of, c.quote_of, c.content_warning, c.sensitive, c.visibility, a.depth + 1
+  FROM chirps c
+  JOIN ancestors a ON c.id = a.parent_id
+)
+SELECT id, created_at, updated_at, body, user_id, parent_id, search_vector, deleted_at, publish_at, rechirp_of, quote_of, content_warning, sensitive, visibility FROM ancestors
+WHERE deleted_at IS NULL AND publish_at IS NULL
+AND chirp_visible_to(id, user_id, visibility, sqlc.arg(viewer_id)::uuid)
+ORDER BY depth DESC;
+
+-- name: GetChirpDescendants :many
+WITH RECURSIVE descendants AS (
+  SELECT id, created_at, updated_at, body, user_id, parent_id, deleted_at, publish_at, quote_of, content_warning, sensitive, visibility, 1 AS depth
+  FROM chirps
+  WHERE chirps.parent_id = sqlc.arg(root_id)::uuid
+  UNION ALL
+  SELECT c.id, c.created_at, c.updated_at, c.body, c.user_id, c.parent_id, c.deleted_at, c.publish_at, c.quote_of, c.content_warning, c.sensitive, c.visibility, d.depth + 1
+  FROM chirps c
+  JOIN descendants d ON c.parent_id = d.id
+)
+SELECT id, created_at, updated_at, body, user_id, parent_id, quote_of, content_warning, sensitive, visibility, depth FROM descendants
+WHERE deleted_at IS NULL AND publish_at IS NULL
+AND chirp_visible_to(id, user_id, visibility, sqlc.arg(viewer_id)::uuid)
+AND (sqlc.narg(cursor_created_at)::timestamp IS NULL OR (created_at, id) > (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetReplyCounts :many
+SELECT parent_id, COUNT(*) AS reply_count FROM chirps
+WHERE parent_id = ANY(sqlc.arg(ids)::uuid[]) AND deleted_at IS NULL AND publish_at IS NULL
+AND chirp_visible_to(id, user_id, visibility, sqlc.arg(viewer_id)::uuid)
+GROUP BY parent_id;
+
+-- name: GetTimelineAscending :many
+SELECT chirps.* FROM chirps
+JOIN follows ON follows.followed_id = chirps.user_id
+WHERE follows.follower_id = sqlc.arg(user_id)
+AND chirps.deleted_at IS NULL AND chirps.publish_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, sqlc.arg(user_id))
+AND (chirps.rechirp_of IS NULL OR EXISTS (SELECT 1 FROM chirps original WHERE original.id = chirps.rechirp_of AND original.deleted_at IS NULL))
+AND (sqlc.narg(cursor_created_at)::timestamp IS NULL OR (chirps.created_at, chirps.id) > (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY chirps.created_at ASC, chirps.id ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetTimelineDescending :many
+SELECT chirps.* FROM chirps
+JOIN follows ON follows.followed_id = chirps.user_id
+WHERE follows.follower_id = sqlc.arg(user_id)
+AND chirps.deleted_at IS NULL AND chirps.publish_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, sqlc.arg(user_id))
+AND (chirps.rechirp_of IS NULL OR EXISTS (SELECT 1 FROM chirps original WHERE original.id = chirps.rechirp_of AND original.deleted_at IS NULL))
+AND (sqlc.narg(cursor_created_at)::timestamp IS NULL OR (chirps.created_at, chirps.id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: SearchChirps :many
+SELECT chirps.*,
+  ts_rank(chirps.search_vector, query) AS rank,
+  ts_headline('english', chirps.body, query, 'StartSel=' || chr(57344) || ', StopSel=' || chr(57345) || ', HighlightAll=true') AS highlight
+FROM chirps, websearch_to_tsquery('english', sqlc.arg(query)::text) query
+WHERE chirps.search_vector @@ query
+AND chirps.deleted_at IS NULL AND chirps.publish_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, sqlc.arg(viewer_id)::uuid)
+AND (sqlc.arg(author_id)::uuid = CAST('00000000-0000-0000-0000-000000000000' as uuid) OR chirps.user_id = sqlc.arg(author_id)::uuid)
+AND (sqlc.narg(cursor_rank)::real IS NULL OR (ts_rank(chirps.search_vector, query), chirps.created_at, chirps.id) < (sqlc.narg(cursor_rank)::real, sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY rank DESC, chirps.created_at DESC, chirps.id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetTagChirpsAscending :many
+SELECT chirps.* FROM chirps
+JOIN chirp_tags ON chirp_tags.chirp_id = chirps.id
+JOIN tags ON tags.id = chirp_tags.tag_id
+WHERE tags.name = sqlc.arg(tag)
+AND chirps.deleted_at IS NULL AND chirps.publish_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, sqlc.arg(viewer_id)::uuid)
+AND (sqlc.narg(cursor_created_at)::timestamp IS NULL OR (chirps.created_at, chirps.id) > (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY chirps.created_at ASC, chirps.id ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetTagChirpsDescending :many
+SELECT chirps.* FROM chirps
+JOIN chirp_tags ON chirp_tags.chirp_id = chirps.id
+JOIN tags ON tags.id = chirp_tags.tag_id
+WHERE tags.name = sqlc.arg(tag)
+AND chirps.deleted_at IS NULL AND chirps.publish_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, sqlc.arg(viewer_id)::uuid)
+AND (sqlc.narg(cursor_created_at)::timestamp IS NULL OR (chirps.created_at, chirps.id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetChirpForUpdate :one
+SELECT * FROM chirps WHERE id = $1 AND deleted_at IS NULL FOR UPDATE;
+
+-- name: UpdateChirpBody :one
+UPDATE chirps SET body = sqlc.arg(body), updated_at = NOW()
+WHERE id = sqlc.arg(id)
+AND (publish_at IS NOT NULL OR sqlc.narg(edit_window_seconds)::float8 IS NULL OR created_at > NOW() - make_interval(secs => sqlc.narg(edit_window_seconds)::float8))
+RETURNING *;
+
+-- name: PublishDueChirps :many
+-- chirps of authors whose email is unverified stay scheduled until they verify;
+-- publish_at is stored in UTC
+UPDATE chirps SET publish_at = NULL, created_at = NOW(), updated_at = NOW()
+WHERE id IN (
+  SELECT id FROM chirps
+  WHERE publish_at <= (NOW() AT TIME ZONE 'UTC') AND deleted_at IS NULL
+  AND EXISTS (SELECT 1 FROM users WHERE users.id = chirps.user_id AND users.email_verified)
+  ORDER BY publish_at ASC
+  LIMIT sqlc.arg(row_limit)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: GetScheduledChirpsAscending :many
+SELECT * FROM chirps
+WHERE user_id = sqlc.arg(user_id)
+AND deleted_at IS NULL AND publish_at IS NOT NULL
+AND (sqlc.narg(cursor_publish_at)::timestamp IS NULL OR (publish_at, id) > (sqlc.narg(cursor_publish_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY publish_at ASC, id ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetScheduledChirpsDescending :many
+SELECT * FROM chirps
+WHERE user_id = sqlc.arg(user_id)
+AND deleted_at IS NULL AND publish_at IS NOT NULL
+AND (sqlc.narg(cursor_publish_at)::timestamp IS NULL OR (publish_at, id) < (sqlc.narg(cursor_publish_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY publish_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: SetChirpContentWarning :one
+UPDATE chirps SET content_warning = sqlc.narg(content_warning), sensitive = sqlc.arg(sensitive)
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+RETURNING *;
@@ -0,0 +1,33 @@
+-- name: CreateDraft :one
+INSERT INTO drafts (id, created_at, updated_at, user_id, body, parent_id, visibility, content_warning, sensitive)
+VALUES (gen_random_uuid(), NOW(), NOW(), sqlc.arg(user_id), sqlc.arg(body), sqlc.narg(parent_id), sqlc.arg(visibility),
+  sqlc.narg(content_warning), sqlc.arg(sensitive))
+RETURNING *;
+
+-- name: GetDraft :one
+SELECT * FROM drafts WHERE id = $1 AND user_id = $2;
+
+-- name: GetDraftsAscending :many
+SELECT * FROM drafts
+WHERE user_id = sqlc.arg(user_id)
+AND (sqlc.narg(cursor_created_at)::timestamp IS NULL OR (created_at, id) > (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetDraftsDescending :many
+SELECT * FROM drafts
+WHERE user_id = sqlc.arg(user_id)
+AND (sqlc.narg(cursor_created_at)::timestamp IS NULL OR (created_at, id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: UpdateDraft :one
+UPDATE drafts SET body = sqlc.arg(body), parent_id = sqlc.narg(parent_id),
+  visibility = sqlc.arg(visibility), content_warning = sqlc.narg(content_warning),
+  sensitive = sqlc.arg(sensitive), updated_at = NOW()
+WHERE id = sqlc.arg(id) AND user_id = sqlc.arg(user_id)
+RETURNING *;
+
+-- name: DeleteDraft :one
+DELETE FROM drafts WHERE id = $1 AND user_id = $2
+RETURNING *;
@@ -0,0 +1,12 @@
+-- name: CreateEmailVerification :one
+INSERT INTO email_verifications (id, created_at, expires_at, user_id, email)
+VALUES (gen_random_uuid(), NOW(), $1, $2, $3)
+RETURNING *;
+
+-- name: UseEmailVerification :one
+UPDATE email_verifications SET used_at = NOW()
+WHERE id = $1 AND used_at IS NULL AND expires_at > NOW()
+RETURNING *;
+
+-- name: DeleteExpiredEmailVerifications :execrows
+DELETE FROM email_verifications WHERE expires_at < NOW();
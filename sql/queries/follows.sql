@@ -0,0 +1,35 @@
+-- name: FollowUser :exec
+INSERT INTO follows (follower_id, followed_id, created_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT (follower_id, followed_id) DO NOTHING;
+
+-- name: UnfollowUser :exec
+DELETE FROM follows WHERE follower_id = $1 AND followed_id = $2;
+
+-- name: GetFollowersAscending :many
+SELECT follower_id AS user_id, created_at FROM follows
+WHERE followed_id = sqlc.arg(user_id)
+AND (sqlc.narg(cursor_created_at)::timestamp IS NULL OR (created_at, follower_id) > (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at ASC, follower_id ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetFollowersDescending :many
+SELECT follower_id AS user_id, created_at FROM follows
+WHERE followed_id = sqlc.arg(user_id)
+AND (sqlc.narg(cursor_created_at)::timestamp IS NULL OR (created_at, follower_id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at DESC, follower_id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetFollowingAscending :many
+SELECT followed_id AS user_id, created_at FROM follows
+WHERE follower_id = sqlc.arg(user_id)
+AND (sqlc.narg(cursor_created_at)::timestamp IS NULL OR (created_at, followed_id) > (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at ASC, followed_id ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetFollowingDescending :many
+SELECT followed_id AS user_id, created_at FROM follows
+WHERE follower_id = sqlc.arg(user_id)
+AND (sqlc.narg(cursor_created_at)::timestamp IS NULL OR (created_at, followed_id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at DESC, followed_id DESC
+LIMIT sqlc.arg(row_limit);
@@ -0,0 +1,83 @@
+-- name: CreateList :one
+INSERT INTO lists (id, created_at, updated_at, user_id, name, private)
+VALUES (gen_random_uuid(), NOW(), NOW(), sqlc.arg(user_id), sqlc.arg(name), sqlc.arg(private))
+RETURNING *;
+
+-- name: GetList :one
+SELECT * FROM lists
+WHERE id = sqlc.arg(id)
+AND (NOT private OR user_id = sqlc.arg(viewer_id));
+
+-- name: GetListsAscending :many
+SELECT * FROM lists
+WHERE (user_id = sqlc.arg(user_id) OR (NOT private AND id IN (SELECT list_id FROM list_follows WHERE list_follows.user_id = sqlc.arg(user_id))))
+AND (sqlc.narg(cursor_created_at)::timestamp IS NULL OR (created_at, id) > (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetListsDescending :many
+SELECT * FROM lists
+WHERE (user_id = sqlc.arg(user_id) OR (NOT private AND id IN (SELECT list_id FROM list_follows WHERE list_follows.user_id = sqlc.arg(user_id))))
+AND (sqlc.narg(cursor_created_at)::timestamp IS NULL OR (created_at, id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: UpdateList :one
+UPDATE lists SET name = sqlc.arg(name), private = sqlc.arg(private), updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteList :exec
+DELETE FROM lists WHERE id = $1;
+
+-- name: AddListMember :exec
+INSERT INTO list_members (list_id, user_id, created_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT (list_id, user_id) DO NOTHING;
+
+-- name: RemoveListMember :exec
+DELETE FROM list_members WHERE list_id = $1 AND user_id = $2;
+
+-- name: GetListMembersAscending :many
+SELECT user_id, created_at FROM list_members
+WHERE list_id = sqlc.arg(list_id)
+AND (sqlc.narg(cursor_created_at)::timestamp IS NULL OR (created_at, user_id) > (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at ASC, user_id ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetListMembersDescending :many
+SELECT user_id, created_at FROM list_members
+WHERE list_id = sqlc.arg(list_id)
+AND (sqlc.narg(cursor_created_at)::timestamp IS NULL OR (created_at, user_id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at DESC, user_id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: FollowList :exec
+INSERT INTO list_follows (list_id, user_id, created_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT (list_id, user_id) DO NOTHING;
+
+-- name: UnfollowList :exec
+DELETE FROM list_follows WHERE list_id = $1 AND user_id = $2;
+
+-- name: GetListChirpsAscending :many
+SELECT chirps.* FROM chirps
+JOIN list_members ON list_members.user_id = chirps.user_id
+WHERE list_members.list_id = sqlc.arg(list_id)
+AND chirps.deleted_at IS NULL AND chirps.publish_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, sqlc.arg(viewer_id)::uuid)
+AND (chirps.rechirp_of IS NULL OR EXISTS (SELECT 1 FROM chirps original WHERE original.id = chirps.rechirp_of AND original.deleted_at IS NULL))
+AND (sqlc.narg(cursor_created_at)::timestamp IS NULL OR (chirps.created_at, chirps.id) > (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY chirps.created_at ASC, chirps.id ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetListChirpsDescending :many
+SELECT chirps.* FROM chirps
+JOIN list_members ON list_members.user_id = chirps.user_id
+WHERE list_members.list_id = sqlc.arg(list_id)
+AND chirps.deleted_at IS NULL AND chirps.publish_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, sqlc.arg(viewer_id)::uuid)
+AND (chirps.rechirp_of IS NULL OR EXISTS (SELECT 1 FROM chirps original WHERE original.id = chirps.rechirp_of AND original.deleted_at IS NULL))
+AND (sqlc.narg(cursor_created_at)::timestamp IS NULL OR (chirps.created_at, chirps.id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT sqlc.arg(row_limit);
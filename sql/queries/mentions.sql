@@ -0,0 +1,24 @@
+-- name: ResolveMentions :many
+-- a name that is nobody's handle falls back to matching the local part of
+-- email addresses
+SELECT id FROM users
+WHERE handle = ANY(sqlc.arg(names)::text[])
+OR (lower(split_part(email, '@', 1)) = ANY(sqlc.arg(names)::text[])
+  AND NOT EXISTS (SELECT 1 FROM users owners WHERE owners.handle = lower(split_part(users.email, '@', 1))));
+
+-- name: AddMention :execrows
+INSERT INTO mentions (chirp_id, user_id, created_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT (chirp_id, user_id) DO NOTHING;
+
+-- name: DeleteChirpMentions :exec
+-- drops the mentions of users no longer in keep, along with the
+-- notifications they were sent for them
+WITH removed AS (
+  DELETE FROM mentions
+  WHERE mentions.chirp_id = sqlc.arg(chirp_id) AND NOT mentions.user_id = ANY(sqlc.arg(keep)::uuid[])
+  RETURNING mentions.user_id
+)
+DELETE FROM notifications
+WHERE notifications.chirp_id = sqlc.arg(chirp_id) AND notifications.kind = sqlc.arg(kind)
+AND notifications.user_id IN (SELECT user_id FROM removed);
@@ -0,0 +1,21 @@
+-- name: GetFilterWords :many
+SELECT word FROM filter_words ORDER BY word;
+
+-- name: AddFilterWords :exec
+INSERT INTO filter_words (word, created_at)
+SELECT unnest(sqlc.arg(words)::text[]), NOW()
+ON CONFLICT (word) DO NOTHING;
+
+-- name: DeleteFilterWord :execrows
+DELETE FROM filter_words WHERE word = $1;
+
+-- name: CreateModerationFlag :exec
+INSERT INTO moderation_flags (id, created_at, chirp_id, words, resolved_at)
+VALUES (gen_random_uuid(), NOW(), $1, $2, NULL);
+
+-- name: GetUnresolvedModerationFlags :many
+SELECT * FROM moderation_flags WHERE resolved_at IS NULL
+ORDER BY created_at ASC, id ASC;
+
+-- name: ResolveModerationFlag :execrows
+UPDATE moderation_flags SET resolved_at = NOW() WHERE id = $1 AND resolved_at IS NULL;
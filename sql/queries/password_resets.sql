@@ -0,0 +1,20 @@
+-- name: CreatePasswordReset :execrows
+-- does nothing if the user was sent a reset within the cooldown
+INSERT INTO password_resets (token_hash, created_at, expires_at, user_id)
+SELECT sqlc.arg(token_hash)::text, NOW(), sqlc.arg(expires_at)::timestamp, sqlc.arg(user_id)::uuid
+WHERE NOT EXISTS (
+  SELECT 1 FROM password_resets
+  WHERE user_id = sqlc.arg(user_id)
+  AND created_at > NOW() - make_interval(secs => sqlc.arg(cooldown_seconds)::float8)
+);
+
+-- name: UsePasswordReset :one
+UPDATE password_resets SET used_at = NOW()
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+RETURNING *;
+
+-- name: UseAllPasswordResets :exec
+UPDATE password_resets SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: DeleteExpiredPasswordResets :execrows
+DELETE FROM password_resets WHERE expires_at < NOW();
@@ -0,0 +1,26 @@
+-- name: PinChirp :execrows
+INSERT INTO pins (user_id, chirp_id, created_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT (user_id, chirp_id) DO NOTHING;
+
+-- name: UnpinChirp :exec
+DELETE FROM pins WHERE user_id = $1 AND chirp_id = $2;
+
+-- name: CountPins :one
+SELECT COUNT(*) FROM pins
+JOIN chirps ON chirps.id = pins.chirp_id
+WHERE pins.user_id = $1 AND chirps.deleted_at IS NULL;
+
+-- name: GetPinnedChirps :many
+SELECT chirps.* FROM pins
+JOIN chirps ON chirps.id = pins.chirp_id
+LEFT JOIN chirps original ON original.id = chirps.rechirp_of
+WHERE pins.user_id = sqlc.arg(user_id)
+AND chirps.deleted_at IS NULL AND chirps.publish_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, sqlc.arg(viewer_id)::uuid)
+AND (NOT sqlc.arg(hide_sensitive)::boolean OR (NOT COALESCE(original.sensitive, chirps.sensitive) AND COALESCE(original.content_warning, chirps.content_warning) IS NULL))
+ORDER BY pins.created_at DESC, pins.chirp_id DESC;
+
+-- name: GetPinnedChirpIDs :many
+SELECT chirp_id FROM pins
+WHERE chirp_id = ANY(sqlc.arg(ids)::uuid[]);
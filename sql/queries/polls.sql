@@ -0,0 +1,43 @@
+-- name: CreatePoll :one
+-- closes_at is in UTC, like the publish_at it is measured from
+INSERT INTO polls (id, chirp_id, created_at, closes_at)
+VALUES (
+  gen_random_uuid(),
+  sqlc.arg(chirp_id),
+  NOW(),
+  COALESCE(sqlc.narg(opens_at)::timestamp, NOW() AT TIME ZONE 'UTC') + make_interval(secs => sqlc.arg(duration_seconds)::float8)
+)
+RETURNING *;
+
+-- name: CreatePollOption :exec
+INSERT INTO poll_options (id, poll_id, position, text)
+VALUES (gen_random_uuid(), $1, $2, $3);
+
+-- name: GetPoll :one
+SELECT polls.*, (polls.closes_at <= (NOW() AT TIME ZONE 'UTC'))::boolean AS closed FROM polls
+WHERE chirp_id = $1;
+
+-- name: GetPollsForChirps :many
+SELECT polls.*, (polls.closes_at <= (NOW() AT TIME ZONE 'UTC'))::boolean AS closed FROM polls
+WHERE chirp_id = ANY(sqlc.arg(ids)::uuid[]);
+
+-- name: GetPollOptions :many
+SELECT poll_options.*, COUNT(poll_votes.user_id) AS votes FROM poll_options
+LEFT JOIN poll_votes ON poll_votes.option_id = poll_options.id
+WHERE poll_options.poll_id = ANY(sqlc.arg(poll_ids)::uuid[])
+GROUP BY poll_options.id
+ORDER BY poll_options.poll_id, poll_options.position;
+
+-- name: GetPollVotes :many
+SELECT poll_id, option_id FROM poll_votes
+WHERE user_id = sqlc.arg(user_id) AND poll_id = ANY(sqlc.arg(poll_ids)::uuid[]);
+
+-- name: VoteInPoll :execrows
+INSERT INTO poll_votes (poll_id, user_id, option_id, created_at, updated_at)
+SELECT polls.id, sqlc.arg(user_id), poll_options.id, NOW(), NOW()
+FROM polls
+JOIN poll_options ON poll_options.poll_id = polls.id
+WHERE polls.chirp_id = sqlc.arg(chirp_id)
+AND poll_options.id = sqlc.arg(option_id)
+AND polls.closes_at > (NOW() AT TIME ZONE 'UTC')
+ON CONFLICT (poll_id, user_id) DO UPDATE SET option_id = EXCLUDED.option_id, updated_at = NOW();
@@ -5,6 +5,22 @@ SELECT * FROM refresh_tokens WHERE token = $1;
 UPDATE refresh_tokens SET revoked_at = NOW(), updated_at = NOW() where token = $1;
 
 -- name: AddRefreshToken :one
-INSERT INTO refresh_tokens (token, created_at, updated_at, user_id, expires_at, revoked_at)
-VALUES ($1, NOW(), NOW(), $2, $3, NULL)
+INSERT INTO refresh_tokens (token, created_at, updated_at, user_id, expires_at, revoked_at, id, user_agent, ip_address, last_used_at)
+VALUES ($1, NOW(), NOW(), $2, $3, NULL, gen_random_uuid(), $4, $5, NOW())
 RETURNING *;
+
+-- name: RevokeUserTokens :exec
+UPDATE refresh_tokens SET revoked_at = NOW(), updated_at = NOW()
+WHERE user_id = $1 AND revoked_at IS NULL;
+
+-- name: TouchRefreshToken :exec
+UPDATE refresh_tokens SET last_used_at = NOW(), ip_address = $1 WHERE token = $2;
+
+-- name: GetSessions :many
+SELECT * FROM refresh_tokens
+WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+ORDER BY last_used_at DESC;
+
+-- name: RevokeSession :execrows
+UPDATE refresh_tokens SET revoked_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
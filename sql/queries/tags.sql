@@ -0,0 +1,27 @@
+-- name: UpsertTag :one
+INSERT INTO tags (id, name, created_at)
+VALUES (gen_random_uuid(), $1, NOW())
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING *;
+
+-- name: AddChirpTag :exec
+INSERT INTO chirp_tags (chirp_id, tag_id, created_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT (chirp_id, tag_id) DO NOTHING;
+
+-- name: GetTrendingTags :many
+SELECT tags.name,
+  COUNT(*) AS uses,
+  SUM(POWER(0.5, EXTRACT(EPOCH FROM (NOW() - chirp_tags.created_at)) / sqlc.arg(half_life_seconds)::float8))::float8 AS score
+FROM chirp_tags
+JOIN tags ON tags.id = chirp_tags.tag_id
+-- trending is the same for everyone, so it only counts chirps anyone can see
+JOIN chirps ON chirps.id = chirp_tags.chirp_id
+WHERE chirp_tags.created_at > NOW() - make_interval(secs => sqlc.arg(window_seconds)::float8)
+AND chirps.visibility = 'public' AND chirps.deleted_at IS NULL
+GROUP BY tags.name
+ORDER BY score DESC, tags.name ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: DeleteChirpTags :exec
+DELETE FROM chirp_tags WHERE chirp_id = $1;
@@ -0,0 +1,45 @@
+-- name: SetTOTPSecret :execrows
+UPDATE users SET totp_secret = $1, updated_at = NOW() WHERE id = $2 AND NOT totp_enabled;
+
+-- name: EnableTOTP :exec
+UPDATE users SET totp_enabled = TRUE, totp_last_step = $1, updated_at = NOW() WHERE id = $2;
+
+-- name: DisableTOTP :exec
+UPDATE users SET totp_secret = NULL, totp_enabled = FALSE, totp_last_step = 0, updated_at = NOW()
+WHERE id = $1;
+
+-- name: UseTOTPStep :execrows
+UPDATE users SET totp_last_step = $1 WHERE id = $2 AND totp_last_step < $1;
+
+-- name: CreateRecoveryCodes :exec
+INSERT INTO recovery_codes (code_hash, created_at, user_id)
+SELECT code_hash, NOW(), sqlc.arg(user_id)::uuid FROM unnest(sqlc.arg(code_hashes)::text[]) AS code_hash;
+
+-- name: UseRecoveryCode :execrows
+UPDATE recovery_codes SET used_at = NOW()
+WHERE code_hash = $1 AND user_id = $2 AND used_at IS NULL;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM recovery_codes WHERE user_id = $1;
+
+-- name: CreateLoginChallenge :one
+INSERT INTO login_challenges (id, created_at, expires_at, user_id)
+VALUES (gen_random_uuid(), NOW(), NOW() + make_interval(secs => sqlc.arg(expiry_seconds)::float8), sqlc.arg(user_id))
+RETURNING *;
+
+-- name: AttemptLoginChallenge :one
+UPDATE login_challenges SET attempts = attempts + 1
+WHERE id = $1 AND user_id = $2 AND used_at IS NULL AND expires_at > NOW()
+RETURNING *;
+
+-- name: UseLoginChallenge :execrows
+UPDATE login_challenges SET used_at = NOW() WHERE id = $1 AND used_at IS NULL;
+
+-- name: CountTwoFactorAttempts :one
+-- attempts on challenges that never succeeded, over the lockout window
+SELECT COALESCE(SUM(attempts), 0)::bigint FROM login_challenges
+WHERE user_id = sqlc.arg(user_id) AND used_at IS NULL
+AND created_at > NOW() - make_interval(secs => sqlc.arg(window_seconds)::float8);
+
+-- name: DeleteOldLoginChallenges :execrows
+DELETE FROM login_challenges WHERE created_at < NOW() - make_interval(secs => sqlc.arg(max_age_seconds)::float8);
@@ -1,11 +1,12 @@
 -- name: CreateUser :one
-INSERT INTO users (id, created_at, updated_at, email, hashed_password)
+INSERT INTO users (id, created_at, updated_at, email, hashed_password, handle)
 VALUES (
   gen_random_uuid(),
   NOW(),
   NOW(),
   $1,
-  $2
+  $2,
+  $3
 )
 RETURNING *;
 
@@ -17,8 +18,31 @@ DELETE FROM users;
 SELECT * FROM users WHERE email = $1;
 
 -- name: UpdateUser :one
-UPDATE users SET email = $1, hashed_password = $2, updated_at = NOW() WHERE id = $3 RETURNING *;
+UPDATE users SET email = sqlc.arg(email), hashed_password = sqlc.arg(hashed_password),
+  handle = sqlc.arg(handle), display_name = sqlc.arg(display_name), bio = sqlc.arg(bio),
+  location = sqlc.arg(location), avatar_id = sqlc.narg(avatar_id),
+  email_verified = sqlc.arg(email_verified), updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
 
 -- name: UpgradeUser :one
 UPDATE users SET is_chirpy_red = TRUE where id = $1 RETURNING *;
 
+
+-- name: GetUser :one
+SELECT * FROM users WHERE id = $1;
+
+-- name: GetUserByHandle :one
+SELECT * FROM users WHERE handle = $1;
+
+-- name: VerifyEmail :execrows
+UPDATE users SET email_verified = TRUE, updated_at = NOW() WHERE id = $1 AND email = $2;
+
+-- name: GetUserForUpdate :one
+SELECT * FROM users WHERE id = $1 FOR UPDATE;
+
+-- name: UpdateSensitiveContent :one
+UPDATE users SET sensitive_content = $1, updated_at = NOW() WHERE id = $2 RETURNING *;
+
+-- name: UpdatePassword :exec
+UPDATE users SET hashed_password = $1, updated_at = NOW() WHERE id = $2;
@@ -0,0 +1,34 @@
+-- name: AddChirpViews :exec
+INSERT INTO chirp_views (chirp_id, day, views)
+SELECT counts.chirp_id, counts.day, counts.views
+FROM unnest(sqlc.arg(chirp_ids)::uuid[], sqlc.arg(days)::date[], sqlc.arg(views)::bigint[]) AS counts(chirp_id, day, views)
+WHERE EXISTS (SELECT 1 FROM chirps WHERE chirps.id = counts.chirp_id)
+ON CONFLICT (chirp_id, day) DO UPDATE SET views = chirp_views.views + EXCLUDED.views;
+
+-- name: GetChirpAnalytics :many
+SELECT activity.chirp_id, activity.day::date AS day,
+  SUM(activity.views)::bigint AS views,
+  SUM(activity.likes)::bigint AS likes,
+  SUM(activity.replies)::bigint AS replies
+FROM (
+  SELECT chirp_views.chirp_id, chirp_views.day, chirp_views.views, 0 AS likes, 0 AS replies
+  FROM chirp_views
+  JOIN chirps ON chirps.id = chirp_views.chirp_id
+  WHERE chirps.user_id = sqlc.arg(user_id) AND chirps.deleted_at IS NULL
+  AND chirp_views.day >= sqlc.arg(since)::date
+  UNION ALL
+  SELECT likes.chirp_id, (likes.created_at::timestamptz AT TIME ZONE 'UTC')::date, 0, 1, 0
+  FROM likes
+  JOIN chirps ON chirps.id = likes.chirp_id
+  WHERE chirps.user_id = sqlc.arg(user_id) AND chirps.deleted_at IS NULL
+  AND (likes.created_at::timestamptz AT TIME ZONE 'UTC')::date >= sqlc.arg(since)::date
+  UNION ALL
+  SELECT chirps.id, (replies.created_at::timestamptz AT TIME ZONE 'UTC')::date, 0, 0, 1
+  FROM chirps replies
+  JOIN chirps ON chirps.id = replies.parent_id
+  WHERE chirps.user_id = sqlc.arg(user_id) AND chirps.deleted_at IS NULL
+  AND (replies.created_at::timestamptz AT TIME ZONE 'UTC')::date >= sqlc.arg(since)::date
+  AND replies.deleted_at IS NULL AND replies.publish_at IS NULL
+) activity
+GROUP BY activity.chirp_id, activity.day
+ORDER BY activity.chirp_id, activity.day;
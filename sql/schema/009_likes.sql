@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE likes (
+  user_id UUID NOT NULL,
+  chirp_id UUID NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (user_id, chirp_id),
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY(chirp_id) REFERENCES chirps(id) ON DELETE CASCADE
+);
+CREATE INDEX likes_chirp_id_idx ON likes (chirp_id);
+
+-- +goose Down
+DROP TABLE likes;
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE tags (
+  id UUID PRIMARY KEY,
+  name TEXT NOT NULL UNIQUE,
+  created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE chirp_tags (
+  chirp_id UUID NOT NULL,
+  tag_id UUID NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (chirp_id, tag_id),
+  FOREIGN KEY(chirp_id) REFERENCES chirps(id) ON DELETE CASCADE,
+  FOREIGN KEY(tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+CREATE INDEX chirp_tags_tag_id_created_at_idx ON chirp_tags (tag_id, created_at);
+
+-- +goose Down
+DROP TABLE chirp_tags;
+DROP TABLE tags;
@@ -0,0 +1,31 @@
+-- +goose Up
+CREATE INDEX users_email_local_part_idx ON users (lower(split_part(email, '@', 1)));
+
+CREATE TABLE mentions (
+  chirp_id UUID NOT NULL,
+  user_id UUID NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (chirp_id, user_id),
+  FOREIGN KEY(chirp_id) REFERENCES chirps(id) ON DELETE CASCADE,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX mentions_user_id_idx ON mentions (user_id);
+
+CREATE TABLE notifications (
+  id UUID PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  user_id UUID NOT NULL,
+  actor_id UUID NOT NULL,
+  kind TEXT NOT NULL,
+  chirp_id UUID,
+  read_at TIMESTAMP,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY(actor_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY(chirp_id) REFERENCES chirps(id) ON DELETE CASCADE
+);
+CREATE INDEX notifications_user_id_created_at_idx ON notifications (user_id, created_at, id);
+
+-- +goose Down
+DROP TABLE notifications;
+DROP TABLE mentions;
+DROP INDEX users_email_local_part_idx;
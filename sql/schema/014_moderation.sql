@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE filter_words (
+  word TEXT PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL
+);
+INSERT INTO filter_words (word, created_at)
+VALUES ('kerfuffle', NOW()), ('sharbert', NOW()), ('fornax', NOW());
+
+CREATE TABLE moderation_flags (
+  id UUID PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  chirp_id UUID NOT NULL,
+  words TEXT[] NOT NULL,
+  resolved_at TIMESTAMP,
+  FOREIGN KEY(chirp_id) REFERENCES chirps(id) ON DELETE CASCADE
+);
+CREATE INDEX moderation_flags_unresolved_idx ON moderation_flags (created_at) WHERE resolved_at IS NULL;
+
+-- +goose Down
+DROP TABLE moderation_flags;
+DROP TABLE filter_words;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE chirps ADD COLUMN publish_at TIMESTAMP;
+CREATE INDEX chirps_publish_at_idx ON chirps (publish_at) WHERE publish_at IS NOT NULL;
+CREATE INDEX chirps_scheduled_idx ON chirps (user_id, publish_at, id) WHERE publish_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX chirps_scheduled_idx;
+DROP INDEX chirps_publish_at_idx;
+ALTER TABLE chirps DROP COLUMN publish_at;
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE drafts (
+  id UUID PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  updated_at TIMESTAMP NOT NULL,
+  user_id UUID NOT NULL,
+  body TEXT NOT NULL,
+  parent_id UUID,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY(parent_id) REFERENCES chirps(id) ON DELETE SET NULL
+);
+CREATE INDEX drafts_user_id_idx ON drafts (user_id, created_at, id);
+
+-- +goose Down
+DROP TABLE drafts;
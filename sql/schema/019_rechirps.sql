@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE chirps ADD COLUMN rechirp_of UUID REFERENCES chirps(id) ON DELETE CASCADE;
+-- quote_of has no foreign key so that a quote keeps pointing at the original
+-- after it is purged and can still render a tombstone for it.
+ALTER TABLE chirps ADD COLUMN quote_of UUID;
+CREATE UNIQUE INDEX chirps_rechirp_idx ON chirps (user_id, rechirp_of) WHERE rechirp_of IS NOT NULL AND deleted_at IS NULL;
+CREATE INDEX chirps_rechirp_of_idx ON chirps (rechirp_of) WHERE rechirp_of IS NOT NULL;
+
+-- +goose Down
+DROP INDEX chirps_rechirp_of_idx;
+DROP INDEX chirps_rechirp_idx;
+ALTER TABLE chirps DROP COLUMN quote_of;
+ALTER TABLE chirps DROP COLUMN rechirp_of;
@@ -0,0 +1,24 @@
+-- +goose Up
+CREATE TABLE bookmarks (
+  user_id UUID NOT NULL,
+  chirp_id UUID NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY(user_id, chirp_id),
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY(chirp_id) REFERENCES chirps(id) ON DELETE CASCADE
+);
+CREATE INDEX bookmarks_user_id_idx ON bookmarks (user_id, created_at, chirp_id);
+
+CREATE TABLE pins (
+  user_id UUID NOT NULL,
+  chirp_id UUID NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY(user_id, chirp_id),
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY(chirp_id) REFERENCES chirps(id) ON DELETE CASCADE
+);
+CREATE INDEX pins_chirp_id_idx ON pins (chirp_id);
+
+-- +goose Down
+DROP TABLE pins;
+DROP TABLE bookmarks;
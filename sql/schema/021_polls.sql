@@ -0,0 +1,35 @@
+-- +goose Up
+CREATE TABLE polls (
+  id UUID PRIMARY KEY,
+  chirp_id UUID NOT NULL UNIQUE,
+  created_at TIMESTAMP NOT NULL,
+  closes_at TIMESTAMP NOT NULL,
+  FOREIGN KEY(chirp_id) REFERENCES chirps(id) ON DELETE CASCADE
+);
+
+CREATE TABLE poll_options (
+  id UUID PRIMARY KEY,
+  poll_id UUID NOT NULL,
+  position INTEGER NOT NULL,
+  text TEXT NOT NULL,
+  UNIQUE(poll_id, position),
+  FOREIGN KEY(poll_id) REFERENCES polls(id) ON DELETE CASCADE
+);
+
+CREATE TABLE poll_votes (
+  poll_id UUID NOT NULL,
+  user_id UUID NOT NULL,
+  option_id UUID NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  updated_at TIMESTAMP NOT NULL,
+  PRIMARY KEY(poll_id, user_id),
+  FOREIGN KEY(poll_id) REFERENCES polls(id) ON DELETE CASCADE,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY(option_id) REFERENCES poll_options(id) ON DELETE CASCADE
+);
+CREATE INDEX poll_votes_option_id_idx ON poll_votes (option_id);
+
+-- +goose Down
+DROP TABLE poll_votes;
+DROP TABLE poll_options;
+DROP TABLE polls;
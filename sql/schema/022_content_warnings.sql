@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE chirps ADD COLUMN content_warning TEXT;
+ALTER TABLE chirps ADD COLUMN sensitive BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN sensitive_content TEXT NOT NULL DEFAULT 'hide';
+ALTER TABLE users ADD CONSTRAINT users_sensitive_content_check CHECK (sensitive_content IN ('hide', 'expand'));
+
+-- +goose Down
+ALTER TABLE users DROP CONSTRAINT users_sensitive_content_check;
+ALTER TABLE users DROP COLUMN sensitive_content;
+ALTER TABLE chirps DROP COLUMN sensitive;
+ALTER TABLE chirps DROP COLUMN content_warning;
@@ -0,0 +1,24 @@
+-- +goose Up
+ALTER TABLE chirps ADD COLUMN visibility TEXT NOT NULL DEFAULT 'public';
+ALTER TABLE chirps ADD CONSTRAINT chirps_visibility_check CHECK (visibility IN ('public', 'followers', 'mentioned'));
+
+-- chirp_visible_to is the single definition of who can read a chirp, shared
+-- by every query that returns chirps. viewer_id is the nil UUID for
+-- anonymous requests, which only ever see public chirps.
+-- +goose StatementBegin
+CREATE FUNCTION chirp_visible_to(target_id UUID, target_user_id UUID, target_visibility TEXT, viewer_id UUID) RETURNS BOOLEAN AS $$
+  SELECT target_visibility = 'public'
+    OR target_user_id = viewer_id
+    OR (target_visibility = 'followers' AND EXISTS (
+      SELECT 1 FROM follows WHERE follows.follower_id = viewer_id AND follows.followed_id = target_user_id
+    ))
+    OR (target_visibility = 'mentioned' AND EXISTS (
+      SELECT 1 FROM mentions WHERE mentions.chirp_id = target_id AND mentions.user_id = viewer_id
+    ))
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP FUNCTION chirp_visible_to;
+ALTER TABLE chirps DROP CONSTRAINT chirps_visibility_check;
+ALTER TABLE chirps DROP COLUMN visibility;
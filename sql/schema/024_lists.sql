@@ -0,0 +1,36 @@
+-- +goose Up
+CREATE TABLE lists (
+  id UUID PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  updated_at TIMESTAMP NOT NULL,
+  user_id UUID NOT NULL,
+  name TEXT NOT NULL,
+  private BOOLEAN NOT NULL DEFAULT false,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX lists_user_id_idx ON lists (user_id, created_at, id);
+
+CREATE TABLE list_members (
+  list_id UUID NOT NULL,
+  user_id UUID NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY(list_id, user_id),
+  FOREIGN KEY(list_id) REFERENCES lists(id) ON DELETE CASCADE,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX list_members_user_id_idx ON list_members (user_id);
+
+CREATE TABLE list_follows (
+  list_id UUID NOT NULL,
+  user_id UUID NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY(list_id, user_id),
+  FOREIGN KEY(list_id) REFERENCES lists(id) ON DELETE CASCADE,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX list_follows_user_id_idx ON list_follows (user_id);
+
+-- +goose Down
+DROP TABLE list_follows;
+DROP TABLE list_members;
+DROP TABLE lists;
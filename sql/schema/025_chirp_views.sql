@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE chirp_views (
+  chirp_id UUID NOT NULL,
+  day DATE NOT NULL,
+  views BIGINT NOT NULL,
+  PRIMARY KEY(chirp_id, day),
+  FOREIGN KEY(chirp_id) REFERENCES chirps(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE chirp_views;
@@ -0,0 +1,37 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN handle TEXT;
+ALTER TABLE users ADD COLUMN display_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN bio TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN location TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN avatar_id UUID REFERENCES attachments(id) ON DELETE SET NULL;
+
+-- existing accounts get the local part of their email address as a handle,
+-- made unique with part of their ID where it is taken or too short
+UPDATE users SET handle = candidates.handle
+FROM (
+  SELECT id,
+    CASE WHEN length(base) >= 3 AND ROW_NUMBER() OVER (PARTITION BY base ORDER BY created_at, id) = 1
+      THEN base
+      ELSE left(base, 23) || '_' || left(replace(id::text, '-', ''), 6)
+    END AS handle
+  FROM (
+    SELECT id, created_at, left(lower(regexp_replace(split_part(email, '@', 1), '[^a-zA-Z0-9_]', '_', 'g')), 30) AS base
+    FROM users
+  ) bases
+) candidates
+WHERE users.id = candidates.id;
+
+ALTER TABLE users ALTER COLUMN handle SET NOT NULL;
+ALTER TABLE users ADD CONSTRAINT users_handle_key UNIQUE (handle);
+
+-- mentions resolve against handles from now on
+DROP INDEX users_email_local_part_idx;
+
+-- +goose Down
+CREATE INDEX users_email_local_part_idx ON users (lower(split_part(email, '@', 1)));
+ALTER TABLE users DROP CONSTRAINT users_handle_key;
+ALTER TABLE users DROP COLUMN avatar_id;
+ALTER TABLE users DROP COLUMN location;
+ALTER TABLE users DROP COLUMN bio;
+ALTER TABLE users DROP COLUMN display_name;
+ALTER TABLE users DROP COLUMN handle;
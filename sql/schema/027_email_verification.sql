@@ -0,0 +1,19 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+-- accounts from before verification existed keep posting as they did
+UPDATE users SET email_verified = TRUE;
+
+CREATE TABLE email_verifications (
+  id UUID PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  expires_at TIMESTAMP NOT NULL,
+  used_at TIMESTAMP,
+  user_id UUID NOT NULL,
+  email TEXT NOT NULL,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX email_verifications_expires_at_idx ON email_verifications (expires_at);
+
+-- +goose Down
+DROP TABLE email_verifications;
+ALTER TABLE users DROP COLUMN email_verified;
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE password_resets (
+  token_hash TEXT PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  expires_at TIMESTAMP NOT NULL,
+  used_at TIMESTAMP,
+  user_id UUID NOT NULL,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX password_resets_user_id_idx ON password_resets (user_id);
+CREATE INDEX refresh_tokens_user_id_idx ON refresh_tokens (user_id);
+
+-- +goose Down
+DROP INDEX refresh_tokens_user_id_idx;
+DROP TABLE password_resets;
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE login_challenges (
+  id UUID PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  expires_at TIMESTAMP NOT NULL,
+  used_at TIMESTAMP,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  user_id UUID NOT NULL,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX login_challenges_user_id_idx ON login_challenges (user_id, created_at);
+
+-- +goose Down
+DROP TABLE login_challenges;
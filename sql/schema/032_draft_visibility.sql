@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE drafts ADD COLUMN visibility TEXT NOT NULL DEFAULT 'public';
+ALTER TABLE drafts ADD CONSTRAINT drafts_visibility_check CHECK (visibility IN ('public', 'followers', 'mentioned'));
+
+-- +goose Down
+ALTER TABLE drafts DROP CONSTRAINT drafts_visibility_check;
+ALTER TABLE drafts DROP COLUMN visibility;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- media is served by key, and each request looks up who may see it
+CREATE UNIQUE INDEX attachments_storage_key_idx ON attachments (storage_key);
+CREATE UNIQUE INDEX attachments_thumbnail_key_idx ON attachments (thumbnail_key);
+
+-- +goose Down
+DROP INDEX attachments_thumbnail_key_idx;
+DROP INDEX attachments_storage_key_idx;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE drafts ADD COLUMN content_warning TEXT;
+ALTER TABLE drafts ADD COLUMN sensitive BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE drafts DROP COLUMN sensitive;
+ALTER TABLE drafts DROP COLUMN content_warning;
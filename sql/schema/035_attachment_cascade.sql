@@ -0,0 +1,11 @@
+-- +goose Up
+-- an attachment whose chirp is gone must not look like a fresh upload that
+-- can be attached to another chirp
+ALTER TABLE attachments DROP CONSTRAINT attachments_chirp_id_fkey;
+ALTER TABLE attachments ADD CONSTRAINT attachments_chirp_id_fkey
+  FOREIGN KEY(chirp_id) REFERENCES chirps(id) ON DELETE CASCADE;
+
+-- +goose Down
+ALTER TABLE attachments DROP CONSTRAINT attachments_chirp_id_fkey;
+ALTER TABLE attachments ADD CONSTRAINT attachments_chirp_id_fkey
+  FOREIGN KEY(chirp_id) REFERENCES chirps(id) ON DELETE SET NULL;
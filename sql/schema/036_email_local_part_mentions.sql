@@ -0,0 +1,7 @@
+-- +goose Up
+-- mentions fall back to the local part of email addresses again when no
+-- handle matches, as they did before handles existed
+CREATE INDEX users_email_local_part_idx ON users (lower(split_part(email, '@', 1)));
+
+-- +goose Down
+DROP INDEX users_email_local_part_idx;
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"servers/internal/database"
+	"servers/internal/pagination"
+	"servers/internal/parse"
+)
+
+const (
+	defaultTrendingWindow = 24 * time.Hour
+	maxTrendingWindow     = 30 * 24 * time.Hour
+)
+
+type TrendingTag struct {
+	Name  string  `json:"name"`
+	Uses  int64   `json:"uses"`
+	Score float64 `json:"score"`
+}
+
+func (cfg *apiConfig) handlerTagChirps(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	tag := parse.NormalizeTag(r.PathValue("tag"))
+	if len(tag) == 0 {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "invalid tag"})
+		w.Write(data)
+		return
+	}
+
+	page, err := parsePageRequest(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+
+	viewerID := cfg.viewer(r)
+	cursorCreatedAt, cursorID := page.cursorArgs()
+	var chirps []database.Chirp
+	if page.ascending() {
+		chirps, err = cfg.db.GetTagChirpsAscending(r.Context(), database.GetTagChirpsAscendingParams{
+			Tag:             tag,
+			ViewerID:        viewerID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        page.rowLimit(),
+		})
+	} else {
+		chirps, err = cfg.db.GetTagChirpsDescending(r.Context(), database.GetTagChirpsDescendingParams{
+			Tag:             tag,
+			ViewerID:        viewerID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        page.rowLimit(),
+		})
+	}
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	cfg.writeChirpPage(w, r, nil, chirps, page)
+}
+
+// handlerTrendingTags ranks tags used within window (a Go duration, 24h by
+// default). Each use is weighted by its age with a half-life of a quarter of
+// the window, so recent bursts outrank steady but older usage.
+func (cfg *apiConfig) handlerTrendingTags(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	type trendingResp struct {
+		Window string        `json:"window"`
+		Tags   []TrendingTag `json:"tags"`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	window := defaultTrendingWindow
+	if windowFromQuery := r.URL.Query().Get("window"); len(windowFromQuery) > 0 {
+		parsed, err := time.ParseDuration(windowFromQuery)
+		if err != nil || parsed <= 0 || parsed > maxTrendingWindow {
+			w.WriteHeader(400)
+			data, _ := json.Marshal(errorResp{Error: "invalid window"})
+			w.Write(data)
+			return
+		}
+		window = parsed
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+
+	rows, err := cfg.db.GetTrendingTags(r.Context(), database.GetTrendingTagsParams{
+		HalfLifeSeconds: (window / 4).Seconds(),
+		WindowSeconds:   window.Seconds(),
+		RowLimit:        int32(limit),
+	})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	tags := make([]TrendingTag, len(rows))
+	for i, row := range rows {
+		tags[i] = TrendingTag{
+			Name:  row.Name,
+			Uses:  row.Uses,
+			Score: row.Score,
+		}
+	}
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(trendingResp{
+		Window: window.String(),
+		Tags:   tags,
+	})
+	w.Write(data)
+}
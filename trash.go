@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"servers/internal/database"
+	"servers/internal/pagination"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultTrashRetention = 30 * 24 * time.Hour
+	trashPurgeInterval    = time.Hour
+)
+
+// runPurger periodically removes data that is past its retention: chirps
+// that have been in the trash for too long, uploads that never made it into
+// a chirp, expired email verification and password reset tokens, and old
+// login challenges. It runs until ctx is cancelled.
+func (cfg *apiConfig) runPurger(ctx context.Context) {
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+	for {
+		cfg.purgeTrash(ctx)
+		cfg.purgeUnattachedMedia(ctx)
+		cfg.purgeEmailVerifications(ctx)
+		cfg.purgePasswordResets(ctx)
+		cfg.purgeLoginChallenges(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeTrash permanently removes chirps that have been in the trash for longer
+// than the retention window, along with their media.
+func (cfg *apiConfig) purgeTrash(ctx context.Context) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Error purging trash: %v", err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	media, err := qtx.DeletePurgedChirpMedia(ctx, cfg.trashRetention.Seconds())
+	if err != nil {
+		log.Printf("Error purging trash: %v", err)
+		return
+	}
+	purged, err := qtx.PurgeDeletedChirps(ctx, cfg.trashRetention.Seconds())
+	if err != nil {
+		log.Printf("Error purging trash: %v", err)
+		return
+	}
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("Error purging trash: %v", err)
+		return
+	}
+
+	// the files go only once the rows are gone for good
+	for _, row := range media {
+		cfg.deleteMedia(ctx, row.StorageKey, row.ThumbnailKey)
+	}
+	if purged > 0 {
+		log.Printf("Purged %d chirps from the trash", purged)
+	}
+}
+
+func (cfg *apiConfig) handlerTrash(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	type trashedChirp struct {
+		Chirp
+		DeletedAt time.Time `json:"deleted_at"`
+		PurgeAt   time.Time `json:"purge_at"`
+	}
+
+	type trashResp struct {
+		Chirps     []trashedChirp `json:"chirps"`
+		NextCursor string         `json:"next_cursor,omitempty"`
+		PrevCursor string         `json:"prev_cursor,omitempty"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	page, err := parsePageRequest(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: err.Error()})
+		w.Write(data)
+		return
+	}
+
+	cursorDeletedAt, cursorID := page.cursorArgs()
+	var chirps []database.Chirp
+	if page.ascending() {
+		chirps, err = cfg.db.GetTrashAscending(r.Context(), database.GetTrashAscendingParams{
+			UserID:           userID,
+			RetentionSeconds: cfg.trashRetention.Seconds(),
+			CursorDeletedAt:  cursorDeletedAt,
+			CursorID:         cursorID,
+			RowLimit:         page.rowLimit(),
+		})
+	} else {
+		chirps, err = cfg.db.GetTrashDescending(r.Context(), database.GetTrashDescendingParams{
+			UserID:           userID,
+			RetentionSeconds: cfg.trashRetention.Seconds(),
+			CursorDeletedAt:  cursorDeletedAt,
+			CursorID:         cursorID,
+			RowLimit:         page.rowLimit(),
+		})
+	}
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	chirps, nextCursor, prevCursor := paginate(chirps, page, func(chirp database.Chirp) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: chirp.DeletedAt.Time, ID: chirp.ID}
+	})
+	trashed := make([]trashedChirp, len(chirps))
+	for i, chirp := range chirps {
+		trashed[i] = trashedChirp{
+			Chirp:     chirpFromDB(chirp),
+			DeletedAt: chirp.DeletedAt.Time,
+			PurgeAt:   chirp.DeletedAt.Time.Add(cfg.trashRetention),
+		}
+	}
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(trashResp{
+		Chirps:     trashed,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	})
+	w.Write(data)
+}
+
+// restoreChirp takes a chirp out of the trash. If it was pinned and the user
+// has pinned others up to their limit since deleting it, it comes back
+// unpinned. The user's row is locked as in pinChirp.
+func (cfg *apiConfig) restoreChirp(ctx context.Context, userID, chirpID uuid.UUID) (database.Chirp, error) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	user, err := qtx.GetUserForUpdate(ctx, userID)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	chirp, err := qtx.RestoreChirp(ctx, database.RestoreChirpParams{
+		ID:               chirpID,
+		RetentionSeconds: cfg.trashRetention.Seconds(),
+	})
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	pins, err := qtx.CountPins(ctx, userID)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	if pins > pinLimitFor(user) {
+		err = qtx.UnpinChirp(ctx, database.UnpinChirpParams{UserID: userID, ChirpID: chirpID})
+		if err != nil {
+			return database.Chirp{}, err
+		}
+	}
+
+	return chirp, tx.Commit()
+}
+
+func (cfg *apiConfig) handlerRestoreChirp(w http.ResponseWriter, r *http.Request) {
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("ID"))
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+	chirp, err := cfg.db.GetChirpIncludingDeleted(r.Context(), chirpID)
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte("Chirp not found"))
+		return
+	}
+
+	// check if the user is the owner of the token
+	if chirp.UserID != userID {
+		w.WriteHeader(403)
+		w.Write([]byte("Unauthorized"))
+		return
+	}
+
+	if !chirp.DeletedAt.Valid {
+		w.WriteHeader(409)
+		w.Write([]byte("Chirp is not deleted"))
+		return
+	}
+
+	chirp, err = cfg.restoreChirp(r.Context(), userID, chirpID)
+	if errors.Is(err, sql.ErrNoRows) {
+		w.WriteHeader(410)
+		w.Write([]byte("Chirp can no longer be restored"))
+		return
+	}
+	// a rechirp the user has since rechirped again
+	if isUniqueViolation(err) {
+		w.WriteHeader(409)
+		w.Write([]byte("Chirp already rechirped"))
+		return
+	}
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	chirpsToReturn, err := cfg.hydrateChirps(r.Context(), userID, []database.Chirp{chirp})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	data, _ := json.Marshal(chirpsToReturn[0])
+	w.Write(data)
+}
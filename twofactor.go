@@ -0,0 +1,463 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"servers/internal/auth"
+	"servers/internal/database"
+
+	"github.com/google/uuid"
+)
+
+const (
+	loginChallengeExpiry = 5 * time.Minute
+	recoveryCodeCount    = 10
+	totpIssuer           = "Chirpy"
+	// After maxTwoFactorAttempts wrong codes within twoFactorLockout, logins
+	// for the account are refused until the window has passed.
+	maxTwoFactorAttempts = 5
+	twoFactorLockout     = 15 * time.Minute
+)
+
+// loginChallenge is returned by POST /api/login instead of tokens when the
+// user has two-factor authentication enabled. The challenge token is
+// exchanged for tokens at POST /api/login/2fa along with a code.
+type loginChallenge struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+}
+
+// startLoginChallenge records a login that is waiting for its second factor
+// and returns the token the client completes it with.
+func (cfg *apiConfig) startLoginChallenge(ctx context.Context, userID uuid.UUID) (string, error) {
+	challenge, err := cfg.db.CreateLoginChallenge(ctx, database.CreateLoginChallengeParams{
+		ExpirySeconds: loginChallengeExpiry.Seconds(),
+		UserID:        userID,
+	})
+	if err != nil {
+		return "", err
+	}
+	return auth.MakeChallengeToken(challenge.ID, userID, cfg.secret, loginChallengeExpiry)
+}
+
+// purgeLoginChallenges removes login challenges that are too old to count
+// towards a lockout.
+func (cfg *apiConfig) purgeLoginChallenges(ctx context.Context) {
+	_, err := cfg.db.DeleteOldLoginChallenges(ctx, twoFactorLockout.Seconds())
+	if err != nil {
+		log.Printf("Error purging login challenges: %v", err)
+	}
+}
+
+// checkSecondFactor reports whether code is either the user's current TOTP
+// code or one of their unused recovery codes, and uses it up so that it
+// cannot be accepted again.
+func (cfg *apiConfig) checkSecondFactor(ctx context.Context, user database.User, code string) (bool, error) {
+	step, err := auth.ValidateTOTP(user.TotpSecret.String, code, time.Now())
+	if err == nil {
+		used, err := cfg.db.UseTOTPStep(ctx, database.UseTOTPStepParams{TotpLastStep: step, ID: user.ID})
+		return used > 0, err
+	}
+	used, err := cfg.db.UseRecoveryCode(ctx, database.UseRecoveryCodeParams{
+		CodeHash: auth.HashToken(auth.NormalizeRecoveryCode(code)),
+		UserID:   user.ID,
+	})
+	return used > 0, err
+}
+
+func (cfg *apiConfig) handlerLoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		ChallengeToken string `json:"challenge_token"`
+		Code           string `json:"code"`
+	}
+
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(400)
+		w.Write([]byte("Invalid params"))
+		return
+	}
+
+	challengeID, userID, err := auth.ValidateChallengeToken(params.ChallengeToken, cfg.secret)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+	// every attempt is counted before the code is checked, so that
+	// concurrent guesses cannot get past the limit
+	_, err = cfg.db.AttemptLoginChallenge(r.Context(), database.AttemptLoginChallengeParams{ID: challengeID, UserID: userID})
+	if err != nil {
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+	attempts, err := cfg.db.CountTwoFactorAttempts(r.Context(), database.CountTwoFactorAttemptsParams{
+		UserID:        userID,
+		WindowSeconds: twoFactorLockout.Seconds(),
+	})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+	if attempts > maxTwoFactorAttempts {
+		w.WriteHeader(429)
+		w.Write([]byte("Too many attempts, try again later"))
+		return
+	}
+	user, err := cfg.db.GetUser(r.Context(), userID)
+	if err != nil {
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	// the challenge was issued when 2FA was on; if it has been turned off
+	// since, the password alone is enough
+	if user.TotpEnabled {
+		ok, err := cfg.checkSecondFactor(r.Context(), user, params.Code)
+		if err != nil {
+			log.Printf("%v", err)
+			w.WriteHeader(500)
+			w.Write([]byte("Server Error - something went wrong"))
+			return
+		}
+		if !ok {
+			w.WriteHeader(401)
+			w.Write([]byte("Incorrect code"))
+			return
+		}
+	}
+
+	used, err := cfg.db.UseLoginChallenge(r.Context(), challengeID)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+	if used == 0 {
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	userToReturn, err := cfg.startSession(r, user)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		w.Write([]byte("Server Error - something went wrong"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	data, _ := json.Marshal(userToReturn)
+	w.Write(data)
+}
+
+// handlerEnrollTwoFactor creates a new TOTP secret for the caller. It only
+// takes effect once a code from it is confirmed.
+func (cfg *apiConfig) handlerEnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	type enrollResp struct {
+		Secret     string `json:"secret"`
+		OTPAuthURI string `json:"otpauth_uri"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	user, err := cfg.db.GetUser(r.Context(), userID)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	secret, err := auth.MakeTOTPSecret()
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	updated, err := cfg.db.SetTOTPSecret(r.Context(), database.SetTOTPSecretParams{
+		TotpSecret: sql.NullString{String: secret, Valid: true},
+		ID:         userID,
+	})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	if updated == 0 {
+		w.WriteHeader(409)
+		data, _ := json.Marshal(errorResp{Error: "Two-factor authentication is already enabled"})
+		w.Write(data)
+		return
+	}
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(enrollResp{
+		Secret:     secret,
+		OTPAuthURI: auth.TOTPURI(secret, totpIssuer, user.Handle),
+	})
+	w.Write(data)
+}
+
+// handlerConfirmTwoFactor turns on two-factor authentication once the caller
+// shows they can generate codes, and returns their recovery codes. This is
+// the only time the recovery codes are shown.
+func (cfg *apiConfig) handlerConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Code string `json:"code"`
+	}
+
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	type confirmResp struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Invalid params"})
+		w.Write(data)
+		return
+	}
+
+	user, err := cfg.db.GetUser(r.Context(), userID)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	if user.TotpEnabled {
+		w.WriteHeader(409)
+		data, _ := json.Marshal(errorResp{Error: "Two-factor authentication is already enabled"})
+		w.Write(data)
+		return
+	}
+	if !user.TotpSecret.Valid {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Enroll an authenticator first"})
+		w.Write(data)
+		return
+	}
+	step, err := auth.ValidateTOTP(user.TotpSecret.String, params.Code, time.Now())
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Incorrect code"})
+		w.Write(data)
+		return
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		codes[i], err = auth.MakeRecoveryCode()
+		if err != nil {
+			log.Printf("%v", err)
+			w.WriteHeader(500)
+			data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+			w.Write(data)
+			return
+		}
+		hashes[i] = auth.HashToken(auth.NormalizeRecoveryCode(codes[i]))
+	}
+
+	tx, err := cfg.dbConn.BeginTx(r.Context(), nil)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	err = qtx.EnableTOTP(r.Context(), database.EnableTOTPParams{TotpLastStep: step, ID: userID})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	err = qtx.DeleteRecoveryCodes(r.Context(), userID)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	err = qtx.CreateRecoveryCodes(r.Context(), database.CreateRecoveryCodesParams{UserID: userID, CodeHashes: hashes})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	w.WriteHeader(200)
+	data, _ := json.Marshal(confirmResp{RecoveryCodes: codes})
+	w.Write(data)
+}
+
+// handlerDisableTwoFactor turns off two-factor authentication. It needs both
+// the password and a code, so that a stolen access token is not enough.
+func (cfg *apiConfig) handlerDisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Password string `json:"password"`
+		Code     string `json:"code"`
+	}
+
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Invalid params"})
+		w.Write(data)
+		return
+	}
+
+	user, err := cfg.db.GetUser(r.Context(), userID)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	if !user.TotpEnabled {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Two-factor authentication is not enabled"})
+		w.Write(data)
+		return
+	}
+	err = auth.CheckPasswordHash(user.HashedPassword, params.Password)
+	if err != nil {
+		w.WriteHeader(403)
+		data, _ := json.Marshal(errorResp{Error: "Incorrect password or code"})
+		w.Write(data)
+		return
+	}
+	ok, err := cfg.checkSecondFactor(r.Context(), user, params.Code)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	if !ok {
+		w.WriteHeader(403)
+		data, _ := json.Marshal(errorResp{Error: "Incorrect password or code"})
+		w.Write(data)
+		return
+	}
+
+	tx, err := cfg.dbConn.BeginTx(r.Context(), nil)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	err = qtx.DisableTOTP(r.Context(), userID)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	err = qtx.DeleteRecoveryCodes(r.Context(), userID)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	w.WriteHeader(204)
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"servers/internal/auth"
+	"servers/internal/database"
+	"servers/internal/mail"
+)
+
+const (
+	emailVerificationPurpose = "verify_email"
+	emailVerificationExpiry  = 24 * time.Hour
+)
+
+const verificationEmail = `Hi @%s,
+
+Confirm that this is your email address by sending the token below to
+POST /api/users/verify:
+
+%s
+
+The token expires in 24 hours. If you did not sign up for Chirpy you can
+ignore this email.
+`
+
+// sendVerification emails the user a single-use token confirming that they
+// own their current email address.
+func (cfg *apiConfig) sendVerification(ctx context.Context, user database.User) error {
+	verification, err := cfg.db.CreateEmailVerification(ctx, database.CreateEmailVerificationParams{
+		ExpiresAt: time.Now().Add(emailVerificationExpiry),
+		UserID:    user.ID,
+		Email:     user.Email,
+	})
+	if err != nil {
+		return err
+	}
+	token, err := auth.MakeEmailToken(verification.ID, emailVerificationPurpose, cfg.secret, emailVerificationExpiry)
+	if err != nil {
+		return err
+	}
+	return cfg.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Confirm your email address",
+		Body:    fmt.Sprintf(verificationEmail, user.Handle, token),
+	})
+}
+
+// purgeEmailVerifications removes verification tokens that can no longer be
+// used.
+func (cfg *apiConfig) purgeEmailVerifications(ctx context.Context) {
+	_, err := cfg.db.DeleteExpiredEmailVerifications(ctx)
+	if err != nil {
+		log.Printf("Error purging email verifications: %v", err)
+	}
+}
+
+func (cfg *apiConfig) handlerVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Token string `json:"token"`
+	}
+
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Invalid params"})
+		w.Write(data)
+		return
+	}
+
+	verificationID, err := auth.ValidateEmailToken(params.Token, emailVerificationPurpose, cfg.secret)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Invalid or expired token"})
+		w.Write(data)
+		return
+	}
+
+	tx, err := cfg.dbConn.BeginTx(r.Context(), nil)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	verification, err := qtx.UseEmailVerification(r.Context(), verificationID)
+	if err != nil {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Invalid or expired token"})
+		w.Write(data)
+		return
+	}
+	// the token only counts for the address it was sent to
+	verified, err := qtx.VerifyEmail(r.Context(), database.VerifyEmailParams{
+		ID:    verification.UserID,
+		Email: verification.Email,
+	})
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	if verified == 0 {
+		w.WriteHeader(400)
+		data, _ := json.Marshal(errorResp{Error: "Invalid or expired token"})
+		w.Write(data)
+		return
+	}
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerResendVerification(w http.ResponseWriter, r *http.Request) {
+	type errorResp struct {
+		Error string `json:"error"`
+	}
+
+	userID, err := cfg.authenticate(r)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(401)
+		w.Write([]byte("invalid token"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	user, err := cfg.db.GetUser(r.Context(), userID)
+	if err != nil {
+		log.Printf("%v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+	if user.EmailVerified {
+		w.WriteHeader(409)
+		data, _ := json.Marshal(errorResp{Error: "Email address already verified"})
+		w.Write(data)
+		return
+	}
+
+	err = cfg.sendVerification(r.Context(), user)
+	if err != nil {
+		log.Printf("Error sending verification email: %v", err)
+		w.WriteHeader(500)
+		data, _ := json.Marshal(errorResp{Error: "Something went wrong"})
+		w.Write(data)
+		return
+	}
+
+	w.WriteHeader(204)
+}
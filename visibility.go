@@ -0,0 +1,24 @@
+package main
+
+import "fmt"
+
+// Values of a chirp's visibility. Who may read a chirp is decided in the
+// database by chirp_visible_to, so every query that returns chirps to a
+// viewer filters on it.
+const (
+	visibilityPublic    = "public"
+	visibilityFollowers = "followers"
+	visibilityMentioned = "mentioned"
+)
+
+// validateVisibility checks the visibility sent with a new chirp or draft.
+// Chirps are public unless asked otherwise.
+func validateVisibility(visibility string) (string, error) {
+	switch visibility {
+	case "":
+		return visibilityPublic, nil
+	case visibilityPublic, visibilityFollowers, visibilityMentioned:
+		return visibility, nil
+	}
+	return "", fmt.Errorf("visibility must be %q, %q or %q", visibilityPublic, visibilityFollowers, visibilityMentioned)
+}